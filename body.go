@@ -0,0 +1,250 @@
+package siebns
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// Attr is a single "Key=Value" line of the section.
+type Attr struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+
+	indent string   // leading whitespace, normally a single tab
+	eol    string   // line ending as it was in the file
+	blanks []string // blank lines following the attribute, as is
+	line   int      // line number in the file, 0 if the attribute was added
+}
+
+// Section is a single "[/path]" block of the Name Server file with its
+// attributes in the order they appear in the file.
+type Section struct {
+	Path  string
+	Attrs []*Attr
+
+	eol    string   // line ending of the section header line
+	blanks []string // blank lines following the section header, as is
+	line   int      // line number of the section header, 0 if the section was added
+}
+
+// body is the parsed contents of the file following the header.
+type body struct {
+	// preamble holds lines between the header and the first section, as
+	// is.  Normally these are blank lines, but a malformed file may have
+	// attributes outside of any section there as well.
+	preamble []string
+	start    int // line number of the first line of the body
+	sections []*Section
+}
+
+// lineError is an error that occurred on the specific line of the file.
+type lineError struct {
+	line int
+	err  error
+}
+
+func (e *lineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.line, e.err)
+}
+
+// Get returns the value of the attribute key.  ok is false if the section
+// has no such attribute.
+func (s *Section) Get(key string) (value string, ok bool) {
+	if a := s.attr(key); a != nil {
+		return a.Value, true
+	}
+	return "", false
+}
+
+// attr returns the attribute key or nil if there's none.
+func (s *Section) attr(key string) *Attr {
+	for _, a := range s.Attrs {
+		if a.Key == key {
+			return a
+		}
+	}
+	return nil
+}
+
+// Line returns the line number of the section header in the file, or 0 if
+// the section was not read from the file.
+func (s *Section) Line() int {
+	return s.line
+}
+
+// Sections returns the sections of the file in the order they appear in the
+// file.
+func (ns *NSFile) Sections() []*Section {
+	return ns.sections
+}
+
+// Section returns the section with the path or nil if there's none.
+func (ns *NSFile) Section(path string) *Section {
+	for _, s := range ns.sections {
+		if s.Path == path {
+			return s
+		}
+	}
+	return nil
+}
+
+// clone returns the deep copy of the body.
+func (b *body) clone() body {
+	c := body{
+		preamble: append([]string(nil), b.preamble...),
+		start:    b.start,
+		sections: make([]*Section, len(b.sections)),
+	}
+	for i, s := range b.sections {
+		cs := *s
+		cs.blanks = append([]string(nil), s.blanks...)
+		cs.Attrs = nil
+		for _, a := range s.Attrs {
+			ca := *a
+			ca.blanks = append([]string(nil), a.blanks...)
+			cs.Attrs = append(cs.Attrs, &ca)
+		}
+		c.sections[i] = &cs
+	}
+	return c
+}
+
+// trimEOL strips the line ending from the line.  Both \r\n and \n are
+// recognised regardless of the file format, as files copied between
+// systems may end up with mixed line endings.  The last line may have no
+// line ending at all.
+func trimEOL(line []byte) []byte {
+	if hasDOSlineEndings(line) {
+		return line[:len(line)-len(crlf)]
+	}
+	return bytes.TrimSuffix(line, crlf[1:])
+}
+
+// isBlank returns true if the line consists of whitespace only.
+func isBlank(line []byte) bool {
+	return len(bytes.Trim(line, " \t")) == 0
+}
+
+// parseSectionHeader returns the path of the section header line "[/path]".
+func parseSectionHeader(line []byte) (string, bool) {
+	if len(line) < 2 || line[0] != '[' || line[len(line)-1] != ']' {
+		return "", false
+	}
+	return string(line[1 : len(line)-1]), true
+}
+
+// parseAttr parses the indented "Key=Value" line.
+func parseAttr(line []byte) (*Attr, bool) {
+	text := bytes.TrimLeft(line, " \t")
+	if len(text) == len(line) {
+		return nil, false // attributes are always indented
+	}
+	eq := bytes.IndexByte(text, '=')
+	if eq <= 0 {
+		return nil, false
+	}
+	return &Attr{
+		indent: string(line[:len(line)-len(text)]),
+		Key:    string(text[:eq]),
+		Value:  string(text[eq+1:]),
+	}, true
+}
+
+// parseBody reads the sections following the header from the line reader.
+// Line endings and blank lines are retained, so that the file could be
+// written back unchanged.
+func parseBody(rd lineReader, hdr *nsHeader) (*body, error) {
+	return parseLines(rd, hdr, nil)
+}
+
+// parseLines reads the sections from the line reader.  If dropped is nil,
+// it fails on the first malformed line, otherwise it skips malformed lines,
+// and lines that can't be recovered, recording them in dropped.
+func parseLines(rd lineReader, hdr *nsHeader, dropped *[]DroppedLine) (*body, error) {
+	if hdr == nil {
+		return nil, errNotInitialised
+	}
+	var (
+		b       = body{start: rd.lineno() + 1}
+		current *Section
+		blanks  = &b.preamble // where the next blank line goes
+		orphans bool          // attributes follow the dropped section header
+	)
+	drop := func(line []byte, err error) error {
+		if dropped == nil {
+			return &lineError{rd.lineno(), err}
+		}
+		*dropped = append(*dropped, DroppedLine{Line: rd.lineno(), Text: string(line), Err: err})
+		return nil
+	}
+	for {
+		raw := rd.readline()
+		if err := rd.err(); err != nil && err != io.EOF {
+			if dropped == nil {
+				return nil, err
+			}
+			// the rest of the file is unreadable.
+			*dropped = append(*dropped, DroppedLine{Line: rd.lineno(), Text: string(trimEOL(raw)), Err: err})
+			break
+		}
+		if len(raw) == 0 {
+			break
+		}
+		line := trimEOL(raw)
+		eol := string(raw[len(line):])
+		if hdr.format.unicode && !utf8.Valid(raw) {
+			if err := drop(line, errInvalidUTF8); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		switch path, isSection := parseSectionHeader(line); {
+		case isSection:
+			current = &Section{Path: path, eol: eol, line: rd.lineno()}
+			b.sections = append(b.sections, current)
+			blanks = &current.blanks
+			orphans = false
+		case isBlank(line):
+			*blanks = append(*blanks, string(raw))
+		default:
+			attr, ok := parseAttr(line)
+			if !ok {
+				if err := drop(line, errMalformedLine); err != nil {
+					return nil, err
+				}
+				// attributes of the garbled section header must not end
+				// up in the previous section.
+				orphans = bytes.HasPrefix(bytes.TrimSpace(line), []byte("["))
+				continue
+			}
+			if orphans {
+				drop(line, errOrphanAttr)
+				continue
+			}
+			if dropped != nil && eol == "" && bytes.Contains(line, []byte(`"`)) {
+				// the last line of the truncated file.
+				if _, err := unquote(attr.Value); err != nil {
+					drop(line, errTruncatedLine)
+					continue
+				}
+			}
+			if current == nil {
+				if dropped != nil {
+					drop(line, errAttrOutsideSection)
+					continue
+				}
+				// kept for Validate to report it.
+				b.preamble = append(b.preamble, string(raw))
+				continue
+			}
+			attr.eol = eol
+			attr.line = rd.lineno()
+			current.Attrs = append(current.Attrs, attr)
+			blanks = &attr.blanks
+		}
+	}
+	return &b, nil
+}
@@ -0,0 +1,153 @@
+package siebns
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testfileSample = `Siebel Name Server Backing File
+16.0.0.0 [23057] ENU
+1.2
+DAMAAAAAAAA=             
+
+[/]
+	Persistence=partial
+	Type=empty
+[/enterprises]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84]
+	Persistence=full
+	Type=string
+	Value="Siebel Enterprise"
+[/enterprises/SBA_84/parameters]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/parameters/MaxTasks]
+	Persistence=full
+	Type=integer
+	Value=100
+`
+
+var bodyOpts = cmp.AllowUnexported(body{}, Section{}, Attr{})
+
+func Test_parseBody(t *testing.T) {
+	type args struct {
+		body string
+		hdr  *nsHeader
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *body
+		wantErr bool
+	}{
+		{"unix",
+			args{"\n[/]\n\tPersistence=partial\n\tType=empty\n[/enterprises]\n\tType=empty\n\n", &nsHeader{}},
+			&body{
+				start:    1,
+				preamble: []string{"\n"},
+				sections: []*Section{
+					{Path: "/", eol: "\n", line: 2, Attrs: []*Attr{
+						{Key: "Persistence", Value: "partial", indent: "\t", eol: "\n", line: 3},
+						{Key: "Type", Value: "empty", indent: "\t", eol: "\n", line: 4},
+					}},
+					{Path: "/enterprises", eol: "\n", line: 5, Attrs: []*Attr{
+						{Key: "Type", Value: "empty", indent: "\t", eol: "\n", blanks: []string{"\n"}, line: 6},
+					}},
+				},
+			},
+			false},
+		{"dos",
+			args{"\r\n[/]\r\n \r\n\tValue=\"a=b\"\r\n", &nsHeader{format: format{dos: true}}},
+			&body{
+				start:    1,
+				preamble: []string{"\r\n"},
+				sections: []*Section{
+					{Path: "/", eol: "\r\n", blanks: []string{" \r\n"}, line: 2, Attrs: []*Attr{
+						{Key: "Value", Value: `"a=b"`, indent: "\t", eol: "\r\n", line: 4},
+					}},
+				},
+			},
+			false},
+		{"no trailing newline",
+			args{"[/]\n\tType=empty", &nsHeader{}},
+			&body{
+				start: 1,
+				sections: []*Section{
+					{Path: "/", eol: "\n", line: 1, Attrs: []*Attr{
+						{Key: "Type", Value: "empty", indent: "\t", line: 2},
+					}},
+				},
+			},
+			false},
+		{"mixed line endings",
+			args{"[/]\n\tType=empty\r\n", &nsHeader{}},
+			&body{
+				start: 1,
+				sections: []*Section{
+					{Path: "/", eol: "\n", line: 1, Attrs: []*Attr{
+						{Key: "Type", Value: "empty", indent: "\t", eol: "\r\n", line: 2},
+					}},
+				},
+			},
+			false},
+		{"empty body", args{"", &nsHeader{}}, &body{start: 1}, false},
+		{"attribute outside section",
+			args{"\tType=empty\n[/]\n", &nsHeader{}},
+			&body{preamble: []string{"\tType=empty\n"}, start: 1, sections: []*Section{{Path: "/", eol: "\n", line: 2}}},
+			false},
+		{"malformed line", args{"[/]\nType=empty\n", &nsHeader{}}, nil, true},
+		{"invalid utf-8", args{"[/\xff]\n", &nsHeader{format: format{unicode: true}}}, nil, true},
+		{"non-unicode file",
+			args{"[/\xff]\n", &nsHeader{}},
+			&body{start: 1, sections: []*Section{{Path: "/\xff", eol: "\n", line: 1}}},
+			false},
+		{"nil header", args{"", nil}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseBody(newLineReader(bytes.NewReader([]byte(tt.args.body))), tt.args.hdr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseBody() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got, bodyOpts); diff != "" {
+				t.Errorf("parseBody() mismatch, (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNSFile_Sections(t *testing.T) {
+	ns := CreateTestNSFile(testfileSample)
+	defer CloseTestNSFile(ns)
+
+	if err := ns.load(); err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, s := range ns.Sections() {
+		paths = append(paths, s.Path)
+	}
+	want := []string{"/", "/enterprises", "/enterprises/SBA_84", "/enterprises/SBA_84/parameters", "/enterprises/SBA_84/parameters/MaxTasks"}
+	if diff := cmp.Diff(want, paths); diff != "" {
+		t.Errorf("Sections() mismatch, (-want,+got):\n%s", diff)
+	}
+
+	s := ns.Section("/enterprises/SBA_84/parameters/MaxTasks")
+	if s == nil {
+		t.Fatal("Section() returned nil")
+	}
+	if v, ok := s.Get("Value"); !ok || v != "100" {
+		t.Errorf("Get() = %q, %v, want %q, true", v, ok, "100")
+	}
+	if _, ok := s.Get("Missing"); ok {
+		t.Error("Get() found missing attribute")
+	}
+	if s := ns.Section("/nonexistent"); s != nil {
+		t.Errorf("Section() = %v, want nil", s)
+	}
+}
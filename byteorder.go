@@ -0,0 +1,35 @@
+package siebns
+
+import "encoding/binary"
+
+// SetByteOrder sets the byte order the encoded size is written in by Save,
+// FixSize and WriteTo, converting the file, i.e. when it is moved between
+// AIX or Solaris (big endian) and Linux or Windows (little endian) hosts.
+// nil keeps the byte order detected when the file was read.
+func (ns *NSFile) SetByteOrder(bo binary.ByteOrder) {
+	ns.byteOrder = bo
+}
+
+// WithByteOrder sets the byte order the encoded size is written in, see
+// SetByteOrder.
+func WithByteOrder(bo binary.ByteOrder) Option {
+	return func(ns *NSFile) {
+		ns.SetByteOrder(bo)
+	}
+}
+
+// applyByteOrder sets the byte order of the header to the one set by
+// SetByteOrder, if any.
+func (ns *NSFile) applyByteOrder() {
+	if ns.byteOrder != nil && ns.header != nil {
+		ns.header.byteOrder = ns.byteOrder
+	}
+}
+
+// byteOrderName returns the name of the byte order, "little" or "big".
+func byteOrderName(bo binary.ByteOrder) string {
+	if bo == binary.BigEndian {
+		return "big"
+	}
+	return "little"
+}
@@ -0,0 +1,77 @@
+package siebns
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithByteOrder(t *testing.T) {
+	little := withValidSize(testfileSample)
+	big := withValidSize(strings.Replace(testfileSample, "DAMAAAAAAAA=", "AAAAAAAAAww=", 1))
+	tests := []struct {
+		name     string
+		contents string
+		bo       binary.ByteOrder
+		correct  bool
+		want     string
+	}{
+		{"little to big", little, binary.BigEndian, false, big},
+		{"big to little", big, binary.LittleEndian, false, little},
+		{"auto", big, nil, true, big},
+		{"same", little, binary.LittleEndian, true, little},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, save := range []string{"Save", "FixSize"} {
+				dir, name := createTestDir(t, tt.contents)
+				defer os.RemoveAll(dir)
+				ns, err := Open(name, WithBackups(NoBackup), WithByteOrder(tt.bo))
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer ns.Close()
+
+				if got := ns.IsHeaderCorrect(); got != tt.correct {
+					t.Errorf("IsHeaderCorrect() = %v, want %v", got, tt.correct)
+				}
+				if save == "Save" {
+					err = ns.Save()
+				} else {
+					_, err = ns.FixSize()
+				}
+				if err != nil {
+					t.Fatalf("%s() error = %v", save, err)
+				}
+				got, err := ioutil.ReadFile(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(tt.want, string(got)); diff != "" {
+					t.Errorf("%s() file mismatch, (-want,+got):\n%s", save, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestNSFile_SetByteOrder(t *testing.T) {
+	ns, err := ParseBytes([]byte(testfileSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns.SetByteOrder(binary.BigEndian)
+	var buf bytes.Buffer
+	if _, err := ns.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := withValidSize(strings.Replace(testfileSample, "DAMAAAAAAAA=", "AAAAAAAAAww=", 1))
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("WriteTo() mismatch, (-want,+got):\n%s", diff)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/rusq/siebns"
+)
+
+var cmdCheck = newCommand("check", "<siebns.dat>", "validate the file and print the issues found")
+
+func init() {
+	cmdCheck.run = runCheck
+}
+
+func runCheck(args []string) error {
+	if len(args) < 1 {
+		cmdCheck.usage()
+		return errors.New("file name is required")
+	}
+	ns, err := siebns.OpenReadOnly(args[0])
+	if err != nil {
+		return fmt.Errorf("file %s:  %s", args[0], err)
+	}
+	defer ns.Close()
+
+	h, _ := ns.Header() // version errors are reported by Validate
+	log.Printf("file %s:  checksum byte order: %s.\n", args[0], byteOrderName(h.ByteOrder))
+
+	findings := ns.Validate()
+	var errs, warns int
+	for _, f := range findings {
+		fmt.Printf("%s: %s\n", args[0], f)
+		if f.Severity >= siebns.SeverityError {
+			errs++
+		} else {
+			warns++
+		}
+	}
+	if errs > 0 {
+		return fmt.Errorf("file %s:  %d error(s), %d warning(s)", args[0], errs, warns)
+	}
+	log.Printf("file %s:  OK:  %d warning(s).\n", args[0], warns)
+	return nil
+}
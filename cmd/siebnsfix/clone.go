@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/rusq/siebns"
+)
+
+var (
+	cmdClone       = newCommand("clone", "<siebns.dat> <enterprise> <from> <to>", "copy the configuration of the server to the new server")
+	cloneW         = addWriteFlags(cmdClone.flags)
+	cloneOverwrite = cmdClone.flags.Bool("overwrite", false, "replace the configuration of the existing server")
+	cloneValues    = make(valueMap)
+)
+
+func init() {
+	cmdClone.flags.Var(cloneValues, "map", "replace `old=new` in the values of the new server, i.e. the host name, may be repeated")
+	cmdClone.run = runClone
+}
+
+// valueMap is the flag value of the repeated old=new mapping.
+type valueMap map[string]string
+
+func (m valueMap) String() string {
+	var pairs []string
+	for old, new := range m {
+		pairs = append(pairs, old+"="+new)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (m valueMap) Set(s string) error {
+	eq := strings.IndexByte(s, '=')
+	if eq <= 0 {
+		return fmt.Errorf("expected old=new, got %q", s)
+	}
+	m[s[:eq]] = s[eq+1:]
+	return nil
+}
+
+func runClone(args []string) error {
+	if len(args) < 4 {
+		cmdClone.usage()
+		return errors.New("file name, enterprise, source and new server names are required")
+	}
+	ns, err := cloneW.open(args[0])
+	if err != nil {
+		return err
+	}
+	defer ns.Close()
+
+	enterprise, from, to := args[1], args[2], args[3]
+	if ns.Server(enterprise, to) != nil && !*cloneOverwrite {
+		return fmt.Errorf("server %s already exists, use -overwrite to replace it", to)
+	}
+	opts := siebns.CloneOptions{Values: cloneValues, Overwrite: *cloneOverwrite}
+	if err := cloneW.edit(ns, func() error { return ns.CloneServer(enterprise, from, to, opts) }); err != nil {
+		return err
+	}
+	log.Printf("file %s:  OK:  server %s cloned to %s.\n", ns.Name(), from, to)
+	return nil
+}
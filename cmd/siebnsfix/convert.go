@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/rusq/siebns"
+)
+
+var (
+	cmdConvert = newCommand("convert", "<siebns.dat>", "convert line endings and the BOM of the file")
+	convertW   = addWriteFlags(cmdConvert.flags)
+	convertEOL = cmdConvert.flags.String("eol", "keep", "line `endings`: lf, crlf or keep")
+	convertBOM = cmdConvert.flags.String("bom", "keep", "byte order mark: on, off or keep")
+)
+
+func init() {
+	cmdConvert.run = runConvert
+}
+
+var eolNames = map[string]siebns.LineEnding{
+	"keep": siebns.KeepEOL,
+	"lf":   siebns.LF,
+	"crlf": siebns.CRLF,
+}
+
+var bomNames = map[string]siebns.BOM{
+	"keep": siebns.KeepBOM,
+	"on":   siebns.AddBOM,
+	"off":  siebns.RemoveBOM,
+}
+
+func runConvert(args []string) error {
+	if len(args) < 1 {
+		cmdConvert.usage()
+		return errors.New("file name is required")
+	}
+	eol, ok := eolNames[*convertEOL]
+	if !ok {
+		return fmt.Errorf("invalid -eol: %s", *convertEOL)
+	}
+	bom, ok := bomNames[*convertBOM]
+	if !ok {
+		return fmt.Errorf("invalid -bom: %s", *convertBOM)
+	}
+
+	ns, err := convertW.open(args[0])
+	if err != nil {
+		return err
+	}
+	defer ns.Close()
+
+	if err := convertW.edit(ns, func() error { return ns.Convert(eol, bom) }); err != nil {
+		return err
+	}
+	log.Printf("file %s:  OK: converted.\n", ns.Name())
+	return nil
+}
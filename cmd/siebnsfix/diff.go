@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rusq/siebns"
+)
+
+var (
+	cmdDiff    = newCommand("diff", "<old siebns.dat> <new siebns.dat>", "print the changes between two files")
+	diffFormat = cmdDiff.flags.String("format", "text", "output `format`: text, sections or json")
+)
+
+func init() {
+	cmdDiff.run = runDiff
+}
+
+func runDiff(args []string) error {
+	if len(args) < 2 {
+		cmdDiff.usage()
+		return errors.New("two file names are required")
+	}
+	var print func(w io.Writer, oldName, newName string, changes []siebns.Change) error
+	switch *diffFormat {
+	case "text":
+		print = printText
+	case "sections":
+		print = printSections
+	case "json":
+		print = printJSON
+	default:
+		return fmt.Errorf("unknown format: %s", *diffFormat)
+	}
+
+	oldNS, err := siebns.OpenReadOnly(args[0])
+	if err != nil {
+		return fmt.Errorf("file %s:  %s", args[0], err)
+	}
+	defer oldNS.Close()
+	newNS, err := siebns.OpenReadOnly(args[1])
+	if err != nil {
+		return fmt.Errorf("file %s:  %s", args[1], err)
+	}
+	defer newNS.Close()
+
+	return print(os.Stdout, args[0], args[1], siebns.Diff(oldNS, newNS))
+}
+
+// printText prints one change per line.
+func printText(w io.Writer, oldName, newName string, changes []siebns.Change) error {
+	for _, c := range changes {
+		if _, err := fmt.Fprintln(w, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printSections prints the changes grouped by section: the section path in
+// brackets, followed by the removed lines prefixed with "-" and the added
+// ones prefixed with "+".  It is not the unified diff, the sections of the
+// files are matched by their paths, not by the line numbers.
+func printSections(w io.Writer, oldName, newName string, changes []siebns.Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", oldName, newName); err != nil {
+		return err
+	}
+	var group string
+	for _, c := range changes {
+		if c.Path != group {
+			group = c.Path
+			if _, err := fmt.Fprintf(w, "[%s]\n", c.Path); err != nil {
+				return err
+			}
+		}
+		var lines []string
+		switch {
+		case c.Key == "":
+			sign := "+"
+			if c.Kind == siebns.Removed {
+				sign = "-"
+			}
+			lines = append(lines, sign+"["+c.Path+"]")
+			for _, a := range c.Attrs {
+				lines = append(lines, sign+"\t"+a.Key+"="+a.Value)
+			}
+		case c.Kind == siebns.Added:
+			lines = append(lines, "+\t"+c.Key+"="+c.New)
+		case c.Kind == siebns.Removed:
+			lines = append(lines, "-\t"+c.Key+"="+c.Old)
+		default:
+			lines = append(lines, "-\t"+c.Key+"="+c.Old, "+\t"+c.Key+"="+c.New)
+		}
+		for _, l := range lines {
+			if _, err := fmt.Fprintln(w, l); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// printJSON prints the changes as the JSON array.
+func printJSON(w io.Writer, oldName, newName string, changes []siebns.Change) error {
+	if changes == nil {
+		changes = []siebns.Change{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(changes)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/rusq/siebns"
+)
+
+var (
+	cmdExplain       = newCommand("explain", "<siebns.dat> <enterprise> <alias>", "print where the value of the parameter comes from")
+	explainServer    = cmdExplain.flags.String("server", "", "`server` the parameter is resolved for")
+	explainComponent = cmdExplain.flags.String("component", "", "`component` the parameter is resolved for")
+)
+
+func init() {
+	cmdExplain.run = runExplain
+}
+
+func runExplain(args []string) error {
+	if len(args) < 3 {
+		cmdExplain.usage()
+		return errors.New("file name, enterprise and parameter alias are required")
+	}
+	ns, err := siebns.OpenReadOnly(args[0])
+	if err != nil {
+		return fmt.Errorf("file %s:  %s", args[0], err)
+	}
+	defer ns.Close()
+
+	enterprise, alias := args[1], args[2]
+	effective, chain, err := ns.EffectiveParam(enterprise, *explainServer, *explainComponent, alias)
+	if err != nil {
+		return fmt.Errorf("parameter %s:  %s", alias, err)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	for _, prm := range chain {
+		value := prm.Value
+		if prm.Path == effective.Path {
+			value += "  (effective)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", prm.Level, prm.Path, value)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/rusq/siebns"
+)
+
+var (
+	cmdExport    = newCommand("export", "<siebns.dat>", "print the file as JSON or YAML")
+	exportFormat = cmdExport.flags.String("format", "json", "output `format`: json or yaml")
+)
+
+func init() {
+	cmdExport.run = runExport
+}
+
+func runExport(args []string) error {
+	if len(args) < 1 {
+		cmdExport.usage()
+		return errors.New("file name is required")
+	}
+	var export func(ns *siebns.NSFile) error
+	switch *exportFormat {
+	case "json":
+		export = func(ns *siebns.NSFile) error { return ns.ExportJSON(os.Stdout) }
+	case "yaml":
+		export = func(ns *siebns.NSFile) error { return ns.ExportYAML(os.Stdout) }
+	default:
+		return fmt.Errorf("unknown format: %s", *exportFormat)
+	}
+
+	ns, err := siebns.OpenReadOnly(args[0])
+	if err != nil {
+		return fmt.Errorf("file %s:  %s", args[0], err)
+	}
+	defer ns.Close()
+
+	if err := export(ns); err != nil {
+		return fmt.Errorf("file %s:  %s", args[0], err)
+	}
+	return nil
+}
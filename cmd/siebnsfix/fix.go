@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+var (
+	cmdFix = newCommand("fix", "<siebns.dat>", "fix the encoded size of the file")
+	fixW   = addWriteFlags(cmdFix.flags)
+)
+
+func init() {
+	cmdFix.run = runFix
+}
+
+func runFix(args []string) error {
+	fmt.Printf("Siebnsfix %s - fix checksum in Siebel Gateway file\n", version)
+	if len(args) < 1 {
+		cmdFix.usage()
+		return errors.New("file name is required")
+	}
+	ns, err := fixW.open(args[0])
+	if err != nil {
+		return err
+	}
+	defer ns.Close()
+
+	if ns.IsHeaderCorrect() {
+		log.Printf("file %s:  OK:  no correction needed.\n", ns.Name())
+		return nil
+	}
+
+	log.Printf("file %s:  correction needed.\n", ns.Name())
+	if err := ns.BodyErr(); err != nil {
+		// the file can't be rewritten without losing the malformed
+		// lines, only the size is fixed.
+		log.Printf("file %s:  %s, run \"siebnsfix check\" for details.\n", ns.Name(), err)
+		if err := fixW.edit(ns, func() error { _, err := ns.FixSize(); return err }); err != nil {
+			return err
+		}
+		log.Printf("file %s:  OK: size updated.\n", ns.Name())
+		return nil
+	}
+	if err := fixW.save(ns); err != nil {
+		return err
+	}
+	log.Printf("file %s:  OK: updated.\n", ns.Name())
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rusq/siebns"
+)
+
+var (
+	cmdImport    = newCommand("import", "<file.json|file.yaml>", "create the file from JSON or YAML made by export")
+	importFormat = cmdImport.flags.String("format", "", "input `format`: json or yaml, detected by the file extension if not set")
+	importOut    = cmdImport.flags.String("o", "", "write the file to `siebns.dat` instead of stdout")
+)
+
+func init() {
+	cmdImport.run = runImport
+}
+
+func runImport(args []string) error {
+	if len(args) < 1 {
+		cmdImport.usage()
+		return errors.New("file name is required")
+	}
+	format := *importFormat
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(args[0])), ".")
+	}
+	var parse func(r io.Reader) (*siebns.NSFile, error)
+	switch format {
+	case "json":
+		parse = siebns.ImportJSON
+	case "yaml", "yml":
+		parse = siebns.ImportYAML
+	default:
+		return fmt.Errorf("unknown format: %q, use -format", format)
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	ns, err := parse(f)
+	if err != nil {
+		return fmt.Errorf("file %s:  %s", args[0], err)
+	}
+	if err := writeOutput(ns, *importOut); err != nil {
+		return err
+	}
+	if *importOut != "" {
+		log.Printf("file %s:  OK:  created.\n", *importOut)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/rusq/siebns"
+)
+
+var (
+	cmdMerge = newCommand("merge", "<base> <ours> <theirs>", "merge the changes made in two copies of the base file")
+	mergeOut = cmdMerge.flags.String("o", "", "write the merged file to `file` instead of stdout")
+)
+
+func init() {
+	cmdMerge.run = runMerge
+}
+
+func runMerge(args []string) error {
+	if len(args) < 3 {
+		cmdMerge.usage()
+		return errors.New("three file names are required")
+	}
+	var files [3]*siebns.NSFile
+	for i, name := range args[:3] {
+		ns, err := siebns.OpenReadOnly(name)
+		if err != nil {
+			return fmt.Errorf("file %s:  %s", name, err)
+		}
+		defer ns.Close()
+		files[i] = ns
+	}
+
+	merged, conflicts, err := siebns.Merge(files[0], files[1], files[2])
+	if err != nil {
+		return err
+	}
+	if err := writeOutput(merged, *mergeOut); err != nil {
+		return err
+	}
+	for _, c := range conflicts {
+		fmt.Fprintf(os.Stderr, "conflict: %s\n", c)
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%d conflict(s), changes of %s were not applied", len(conflicts), args[2])
+	}
+	if *mergeOut != "" {
+		log.Printf("file %s:  OK:  merged.\n", *mergeOut)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/rusq/siebns"
+)
+
+var (
+	cmdOverrides       = newCommand("overrides", "<siebns.dat>", "print every level each parameter is set at, and remove redundant overrides")
+	overridesW         = addWriteFlags(cmdOverrides.flags)
+	overridesRedundant = cmdOverrides.flags.Bool("redundant", false, "only print the redundant overrides")
+	overridesRemove    = cmdOverrides.flags.Bool("remove", false, "remove the redundant overrides and save the file")
+)
+
+func init() {
+	cmdOverrides.run = runOverrides
+}
+
+func runOverrides(args []string) error {
+	if len(args) < 1 {
+		cmdOverrides.usage()
+		return errors.New("file name is required")
+	}
+	if *overridesRemove {
+		return removeRedundant(args[0])
+	}
+	ns, err := siebns.OpenReadOnly(args[0])
+	if err != nil {
+		return fmt.Errorf("file %s:  %s", args[0], err)
+	}
+	defer ns.Close()
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	for _, ao := range ns.OverrideReport() {
+		var lines []string
+		for _, o := range ao.Overrides {
+			if *overridesRedundant && !o.Redundant {
+				continue
+			}
+			line := fmt.Sprintf("  %s\t%s\t%s", o.Level, o.Path, o.Value)
+			if o.Inherited != nil {
+				line += fmt.Sprintf("\toverrides %s (%s)", o.Inherited.Value, o.Inherited.Level)
+			}
+			if o.Redundant {
+				line += ", redundant"
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(tw, "%s\n", ao.Alias)
+		for _, line := range lines {
+			fmt.Fprintf(tw, "%s\n", line)
+		}
+	}
+	return tw.Flush()
+}
+
+// removeRedundant removes the redundant overrides from the file name.
+func removeRedundant(name string) error {
+	ns, err := overridesW.open(name)
+	if err != nil {
+		return err
+	}
+	defer ns.Close()
+
+	var removed []siebns.Param
+	if err := overridesW.edit(ns, func() (err error) {
+		removed, err = ns.RemoveRedundant()
+		return err
+	}); err != nil {
+		return err
+	}
+	for _, prm := range removed {
+		fmt.Printf("removed %s = %s\n", prm.Path, prm.Value)
+	}
+	log.Printf("file %s:  OK:  %d redundant override(s) removed.\n", ns.Name(), len(removed))
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/rusq/siebns"
+)
+
+var (
+	cmdParams        = newCommand("params", "<siebns.dat>", "print all parameters as CSV for the spreadsheet review")
+	paramsTSV        = cmdParams.flags.Bool("tsv", false, "separate fields with tabs instead of commas")
+	paramsNonDefault = cmdParams.flags.Bool("non-default", false, "only print parameters that differ from the enterprise-level value")
+)
+
+func init() {
+	cmdParams.run = runParams
+}
+
+func runParams(args []string) error {
+	if len(args) < 1 {
+		cmdParams.usage()
+		return errors.New("file name is required")
+	}
+	ns, err := siebns.OpenReadOnly(args[0])
+	if err != nil {
+		return fmt.Errorf("file %s:  %s", args[0], err)
+	}
+	defer ns.Close()
+
+	params := ns.Params()
+	if *paramsNonDefault {
+		params = siebns.NonDefault(params)
+	}
+	comma := ','
+	if *paramsTSV {
+		comma = '\t'
+	}
+	return siebns.WriteParamsCSV(os.Stdout, params, comma)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/rusq/siebns"
+)
+
+var (
+	cmdRemoveServer  = newCommand("remove-server", "<siebns.dat> <enterprise> <server>", "remove the server and the references to it")
+	removeServerW    = addWriteFlags(cmdRemoveServer.flags)
+	removeServerRefs = cmdRemoveServer.flags.Bool("references", false, "remove the references to the server, otherwise they are only reported")
+	removeServerDry  = cmdRemoveServer.flags.Bool("dry-run", false, "print the summary without changing the file")
+)
+
+func init() {
+	cmdRemoveServer.run = runRemoveServer
+}
+
+func runRemoveServer(args []string) error {
+	if len(args) < 3 {
+		cmdRemoveServer.usage()
+		return errors.New("file name, enterprise and server name are required")
+	}
+	ns, err := removeServerW.open(args[0])
+	if err != nil {
+		return err
+	}
+	defer ns.Close()
+
+	enterprise, server := args[1], args[2]
+	opts := siebns.RemoveOptions{RemoveReferences: *removeServerRefs, DryRun: true}
+	sum, err := ns.RemoveServer(enterprise, server, opts)
+	if err != nil {
+		return err
+	}
+	for _, p := range sum.Sections {
+		fmt.Printf("- [%s]\n", p)
+	}
+	var kept int
+	for _, ref := range sum.References {
+		fmt.Printf("reference: %s\n", ref)
+		if !ref.Removed {
+			kept++
+		}
+	}
+	if *removeServerDry {
+		return nil
+	}
+
+	opts.DryRun = false
+	if err := removeServerW.edit(ns, func() error {
+		_, err := ns.RemoveServer(enterprise, server, opts)
+		return err
+	}); err != nil {
+		return err
+	}
+	log.Printf("file %s:  OK:  server %s removed, %d section(s), %d reference(s) left to review.\n", ns.Name(), server, len(sum.Sections), kept)
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/rusq/siebns"
+)
+
+var (
+	cmdRepair = newCommand("repair", "<siebns.dat>", "rebuild the corrupt checksum line")
+	repairW   = addWriteFlags(cmdRepair.flags)
+)
+
+func init() {
+	cmdRepair.run = runRepair
+}
+
+func runRepair(args []string) error {
+	if len(args) < 1 {
+		cmdRepair.usage()
+		return errors.New("file name is required")
+	}
+	opts, err := repairW.options()
+	if err != nil {
+		return err
+	}
+	ns, err := siebns.Repair(args[0], opts...)
+	if err != nil {
+		if siebns.IsGatewayRunning(err) {
+			return gatewayError(args[0], err)
+		}
+		return fmt.Errorf("file %s:  %s", args[0], err)
+	}
+	defer ns.Close()
+
+	logBackup(ns)
+	log.Printf("file %s:  OK: repaired.\n", ns.Name())
+	return nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/rusq/siebns"
+)
+
+var (
+	cmdSalvage    = newCommand("salvage", "<siebns.dat>", "recover the sections of the truncated or garbled file")
+	salvageOut    = cmdSalvage.flags.String("o", "", "write the cleaned file to `file` instead of stdout")
+	salvageReport = cmdSalvage.flags.String("report", "", "write the dropped lines to `file` instead of stderr")
+)
+
+func init() {
+	cmdSalvage.run = runSalvage
+}
+
+func runSalvage(args []string) error {
+	if len(args) < 1 {
+		cmdSalvage.usage()
+		return errors.New("file name is required")
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ns, dropped, err := siebns.Salvage(f)
+	if err != nil {
+		return fmt.Errorf("file %s:  %s", args[0], err)
+	}
+	if err := writeOutput(ns, *salvageOut); err != nil {
+		return err
+	}
+
+	var report io.Writer = os.Stderr
+	if *salvageReport != "" {
+		rf, err := os.Create(*salvageReport)
+		if err != nil {
+			return err
+		}
+		defer rf.Close()
+		report = rf
+	}
+	for _, d := range dropped {
+		fmt.Fprintf(report, "%s: %s\n", args[0], d)
+	}
+	log.Printf("file %s:  %d section(s) recovered, %d line(s) dropped.\n", args[0], len(ns.Sections()), len(dropped))
+	return nil
+}
@@ -1,38 +1,210 @@
 package main
 
 import (
+	"encoding/binary"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/rusq/siebns"
 )
 
 const version = "2.0.0"
 
-func main() {
-	fmt.Printf("Siebnsfix %s - fix checksum in Siebel Gateway file\n", version)
-	if len(os.Args) < 2 {
-		fmt.Printf("\nUsage: %s <siebns.dat>\n", filepath.Base(os.Args[0]))
-		os.Exit(1)
+// command is the siebnsfix command.
+type command struct {
+	name  string
+	args  string // arguments synopsis
+	short string // one line description
+	flags *flag.FlagSet
+	run   func(args []string) error
+}
+
+// commands is the list of commands, the first one is the default.
+var commands = []*command{
+	cmdFix,
+	cmdCheck,
+	cmdDiff,
+	cmdMerge,
+	cmdExport,
+	cmdImport,
+	cmdParams,
+	cmdConvert,
+	cmdRepair,
+	cmdSalvage,
+	cmdExplain,
+	cmdOverrides,
+	cmdClone,
+	cmdRemoveServer,
+}
+
+// newCommand returns the new command, run is set by the init of the
+// command file.
+func newCommand(name, args, short string) *command {
+	cmd := &command{
+		name:  name,
+		args:  args,
+		short: short,
+		flags: flag.NewFlagSet(name, flag.ExitOnError),
 	}
-	ns, err := siebns.Open(os.Args[1])
-	if err != nil {
-		log.Fatalf("%s", err)
+	cmd.flags.Usage = cmd.usage
+	return cmd
+}
+
+func (cmd *command) usage() {
+	w := cmd.flags.Output()
+	fmt.Fprintf(w, "\nUsage: %s %s [flags] %s\n\n%s.\n", filepath.Base(os.Args[0]), cmd.name, cmd.args, cmd.short)
+	cmd.flags.PrintDefaults()
+}
+
+// writeFlags are the flags of commands that write the file.
+type writeFlags struct {
+	backups   *int
+	wait      *time.Duration
+	force     *bool
+	port      *int
+	byteOrder *string
+}
+
+func addWriteFlags(fs *flag.FlagSet) *writeFlags {
+	return &writeFlags{
+		backups: fs.Int("backups", siebns.KeepAllBackups,
+			"number of `backups` of the file to keep, 0 keeps all, -1 disables backups"),
+		wait: fs.Duration("wait", 0,
+			"wait up to `duration` for the lock held by another siebnsfix"),
+		force: fs.Bool("force", false,
+			"write the file even if the Siebel Gateway Name Server is running"),
+		port: fs.Int("port", siebns.DefaultGatewayPort,
+			"Siebel Gateway Name Server `port`"),
+		byteOrder: fs.String("byte-order", "auto",
+			"byte `order` of the checksum: little, big, or auto to keep the detected one"),
 	}
-	defer ns.Close()
+}
 
-	if ns.IsHeaderCorrect() {
-		log.Printf("file %s:  OK:  no correction needed.\n", ns.Name())
-		return
+// byteOrders are the values of the -byte-order flag.
+var byteOrders = map[string]binary.ByteOrder{
+	"auto":   nil,
+	"little": binary.LittleEndian,
+	"big":    binary.BigEndian,
+}
+
+// byteOrderName returns the name of the byte order for the output.
+func byteOrderName(bo binary.ByteOrder) string {
+	if bo == binary.BigEndian {
+		return "big endian"
 	}
+	return "little endian"
+}
 
-	log.Printf("file %s:  correction needed.\n", ns.Name())
-	wrote, err := ns.FixSize()
+// options returns the options of siebns.Open set by the flags.
+func (wf *writeFlags) options() ([]siebns.Option, error) {
+	bo, ok := byteOrders[*wf.byteOrder]
+	if !ok {
+		return nil, fmt.Errorf("invalid -byte-order: %s", *wf.byteOrder)
+	}
+	check := siebns.WithWriteCheck(siebns.GatewayCheck{Port: *wf.port})
+	if *wf.force {
+		check = siebns.WithWriteCheck(nil)
+	}
+	return []siebns.Option{
+		siebns.WithBackups(*wf.backups),
+		siebns.WithLock(*wf.wait),
+		siebns.WithByteOrder(bo),
+		check,
+	}, nil
+}
+
+// open opens the file for writing.
+func (wf *writeFlags) open(name string) (*siebns.NSFile, error) {
+	opts, err := wf.options()
 	if err != nil {
-		log.Fatalf("error writing to file:  %s\n", err)
+		return nil, err
+	}
+	return siebns.Open(name, opts...)
+}
+
+// save saves the file.
+func (wf *writeFlags) save(ns *siebns.NSFile) error {
+	return wf.edit(ns, ns.Save)
+}
+
+// edit runs the change, that saves the file.
+func (wf *writeFlags) edit(ns *siebns.NSFile, change func() error) error {
+	if err := change(); err != nil {
+		if siebns.IsGatewayRunning(err) {
+			return gatewayError(ns.Name(), err)
+		}
+		return fmt.Errorf("error writing to file:  %s", err)
+	}
+	logBackup(ns)
+	return nil
+}
+
+// gatewayError returns the error of the gateway check for the file name.
+func gatewayError(name string, err error) error {
+	return fmt.Errorf("file %s:  %s, use -force to write anyway", name, err)
+}
+
+// logBackup logs the name of the backup made by Save, and the error
+// removing the old backups, if any.
+func logBackup(ns *siebns.NSFile) {
+	if ns.Backup() != "" {
+		log.Printf("file %s:  original saved as %s.\n", ns.Name(), ns.Backup())
+	}
+	if err := ns.PruneErr(); err != nil {
+		log.Printf("file %s:  unable to remove old backups:  %s.\n", ns.Name(), err)
+	}
+}
+
+// writeOutput writes the file, that was not opened from disk, to the file
+// name, or to stdout if name is empty.
+func writeOutput(ns *siebns.NSFile, name string) error {
+	var w io.Writer = os.Stdout
+	if name != "" {
+		f, err := os.Create(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if _, err := ns.WriteTo(w); err != nil {
+		return fmt.Errorf("error writing the file:  %s", err)
 	}
-	log.Printf("file %s:  OK: updated %d bytes.\n", ns.Name(), wrote)
+	return nil
+}
 
+func main() {
+	args := os.Args[1:]
+	cmd := commands[0]
+	if len(args) > 0 {
+		if args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+			usage()
+			return
+		}
+		for _, c := range commands {
+			if c.name == args[0] {
+				cmd, args = c, args[1:]
+				break
+			}
+		}
+	}
+	cmd.flags.Parse(args)
+	if err := cmd.run(cmd.flags.Args()); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Printf("Siebnsfix %s - fix checksum in Siebel Gateway file\n", version)
+	fmt.Printf("\nUsage: %s [command] [flags] <siebns.dat>\n\nCommands:\n", filepath.Base(os.Args[0]))
+	for _, c := range commands {
+		fmt.Printf("  %-14s %s\n", c.name, c.short)
+	}
+	fmt.Printf("\nThe default command is %s.  Run \"%s <command> -h\" for the command flags.\n",
+		commands[0].name, filepath.Base(os.Args[0]))
 }
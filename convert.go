@@ -0,0 +1,96 @@
+package siebns
+
+import "unicode/utf8"
+
+// LineEnding is the line ending to convert the file to.
+type LineEnding int
+
+// Line endings.
+const (
+	KeepEOL LineEnding = iota // keep line endings as they are
+	LF                        // Unix line endings
+	CRLF                      // DOS line endings
+)
+
+// BOM is the byte order mark conversion.
+type BOM int
+
+// BOM conversions.
+const (
+	KeepBOM   BOM = iota // keep the BOM as it is
+	AddBOM               // add the BOM, making the file unicode
+	RemoveBOM            // remove the BOM
+)
+
+// Convert converts line endings and the BOM of the file and saves it.
+// With eol other than KeepEOL, every line, including blank lines and the
+// last line, gets the same line ending.  The BOM is only added if the file
+// is valid UTF-8.
+func (ns *NSFile) Convert(eol LineEnding, bom BOM) error {
+	return ns.edit(func() error { return ns.convert(eol, bom) })
+}
+
+func (ns *NSFile) convert(eol LineEnding, bom BOM) error {
+	hdr := ns.header
+	if hdr == nil {
+		return errNotInitialised
+	}
+	if bom == AddBOM && !hdr.format.unicode && !ns.body.validUTF8() {
+		return errInvalidUTF8
+	}
+	switch bom {
+	case AddBOM:
+		hdr.format.unicode = true
+	case RemoveBOM:
+		hdr.format.unicode = false
+	}
+	if eol != KeepEOL {
+		hdr.format.dos = eol == CRLF
+		ns.body.setEOL(hdr.eol())
+	}
+	return nil
+}
+
+// setEOL sets the line ending of every line of the body to eol.
+func (b *body) setEOL(eol string) {
+	setBlanks := func(blanks []string) {
+		for i, bl := range blanks {
+			blanks[i] = string(trimEOL([]byte(bl))) + eol
+		}
+	}
+	setBlanks(b.preamble)
+	for _, s := range b.sections {
+		s.eol = eol
+		setBlanks(s.blanks)
+		for _, a := range s.Attrs {
+			a.eol = eol
+			setBlanks(a.blanks)
+		}
+	}
+}
+
+// validUTF8 returns true if all lines of the body are valid UTF-8.
+func (b *body) validUTF8() bool {
+	valid := func(lines []string) bool {
+		for _, l := range lines {
+			if !utf8.ValidString(l) {
+				return false
+			}
+		}
+		return true
+	}
+	if !valid(b.preamble) {
+		return false
+	}
+	for _, s := range b.sections {
+		if !utf8.ValidString(s.Path) || !valid(s.blanks) {
+			return false
+		}
+		for _, a := range s.Attrs {
+			if !utf8.ValidString(a.Key) || !utf8.ValidString(a.Value) || !valid(a.blanks) {
+				return false
+			}
+		}
+	}
+	return true
+}
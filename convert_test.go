@@ -0,0 +1,59 @@
+package siebns
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNSFile_Convert(t *testing.T) {
+	const bomb = "\xef\xbb\xbf"
+	dos := strings.Replace(testfileSample, "\n", "\r\n", -1)
+	// mixed line endings, blank lines and the unterminated last line.
+	mixed := strings.Replace(testfileSample, "[/enterprises]\n", "[/enterprises]\r\n\r\n", 1)
+	mixed = strings.Replace(mixed, "\tType=empty\n[/enterprises/SBA_84]", "\tType=empty\r\n\n[/enterprises/SBA_84]", 1)
+	mixed = strings.TrimSuffix(mixed, "\n")
+	unix := strings.Replace(testfileSample, "[/enterprises]\n", "[/enterprises]\n\n", 1)
+	unix = strings.Replace(unix, "\tType=empty\n[/enterprises/SBA_84]", "\tType=empty\n\n[/enterprises/SBA_84]", 1)
+
+	tests := []struct {
+		name     string
+		contents string
+		eol      LineEnding
+		bom      BOM
+		want     string
+		wantErr  error
+	}{
+		{"to crlf with bom", testfileSample, CRLF, AddBOM, bomb + dos, nil},
+		{"to lf without bom", bomb + dos, LF, RemoveBOM, testfileSample, nil},
+		{"keep", bomb + dos, KeepEOL, KeepBOM, bomb + dos, nil},
+		{"mixed to lf", mixed, LF, KeepBOM, unix, nil},
+		{"mixed to crlf", mixed, CRLF, KeepBOM, strings.Replace(unix, "\n", "\r\n", -1), nil},
+		{"invalid utf-8", testfileSample + "[/\xff]\n", KeepEOL, AddBOM, testfileSample + "[/\xff]\n", errInvalidUTF8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, name := createTestDir(t, withValidSize(tt.contents))
+			defer os.RemoveAll(dir)
+			ns, err := Open(name, WithBackups(NoBackup))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ns.Close()
+
+			if err := ns.Convert(tt.eol, tt.bom); err != tt.wantErr {
+				t.Errorf("Convert() error = %v, want %v", err, tt.wantErr)
+			}
+			got, err := ioutil.ReadFile(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(withValidSize(tt.want), string(got)); diff != "" {
+				t.Errorf("converted file mismatch, (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
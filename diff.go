@@ -0,0 +1,164 @@
+package siebns
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// ChangeKind is the kind of the change found by Diff.
+type ChangeKind int
+
+// Change kinds.
+const (
+	Added ChangeKind = iota
+	Removed
+	Changed
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	default:
+		return "ChangeKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (k ChangeKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// Change is the single difference between two files.  The change is either
+// the section change, when Key is empty, or the attribute change.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	Path string     `json:"path"`
+	Key  string     `json:"key,omitempty"`
+	Old  string     `json:"old"` // old attribute value
+	New  string     `json:"new"` // new attribute value
+	// Attrs are the attributes of the added or removed section.
+	Attrs []Attr `json:"attrs,omitempty"`
+}
+
+// String returns the change in the form "+ [path] Key=value" for added,
+// "- [path] Key=value" for removed and "~ [path] Key: old -> new" for
+// changed attributes, or "+ [path]" and "- [path]" for sections.
+func (c Change) String() string {
+	switch {
+	case c.Key == "" && c.Kind == Added:
+		return "+ [" + c.Path + "]"
+	case c.Key == "" && c.Kind == Removed:
+		return "- [" + c.Path + "]"
+	case c.Key == "":
+		return "~ [" + c.Path + "]"
+	case c.Kind == Added:
+		return fmt.Sprintf("+ [%s] %s=%s", c.Path, c.Key, c.New)
+	case c.Kind == Removed:
+		return fmt.Sprintf("- [%s] %s=%s", c.Path, c.Key, c.Old)
+	default:
+		return fmt.Sprintf("~ [%s] %s: %s -> %s", c.Path, c.Key, c.Old, c.New)
+	}
+}
+
+// Diff returns the changes that turn a into b.  Sections are matched by
+// their paths and attributes by their keys, so neither the order of
+// sections and attributes nor the formatting of the file, including the
+// encoded size, affect the result.  Changes are ordered by the section
+// path, the section changes go before the changes of its attributes.
+func Diff(a, b *NSFile) []Change {
+	return diffSections(a.sections, b.sections)
+}
+
+func diffSections(a, b []*Section) []Change {
+	as, bs := sectionMap(a), sectionMap(b)
+
+	paths := make([]string, 0, len(as)+len(bs))
+	for p := range as {
+		paths = append(paths, p)
+	}
+	for p := range bs {
+		if _, ok := as[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	var changes []Change
+	for _, p := range paths {
+		sa, sb := as[p], bs[p]
+		switch {
+		case sa == nil:
+			changes = append(changes, Change{Kind: Added, Path: p, Attrs: attrValues(sb.Attrs)})
+		case sb == nil:
+			changes = append(changes, Change{Kind: Removed, Path: p, Attrs: attrValues(sa.Attrs)})
+		default:
+			changes = append(changes, diffAttrs(p, sa.Attrs, sb.Attrs)...)
+		}
+	}
+	return changes
+}
+
+// diffAttrs returns the changes of the attributes of the section p.
+// Changed and added attributes go in the order of b, followed by removed
+// ones in the order of a.
+func diffAttrs(p string, a, b []*Attr) []Change {
+	av := make(map[string]string, len(a))
+	for _, attr := range a {
+		if _, dup := av[attr.Key]; !dup {
+			av[attr.Key] = attr.Value
+		}
+	}
+	bv := make(map[string]bool, len(b))
+
+	var changes []Change
+	for _, attr := range b {
+		if bv[attr.Key] {
+			continue
+		}
+		bv[attr.Key] = true
+		old, ok := av[attr.Key]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Kind: Added, Path: p, Key: attr.Key, New: attr.Value})
+		case old != attr.Value:
+			changes = append(changes, Change{Kind: Changed, Path: p, Key: attr.Key, Old: old, New: attr.Value})
+		}
+	}
+	for _, attr := range a {
+		if !bv[attr.Key] {
+			bv[attr.Key] = true // report the duplicate once
+			changes = append(changes, Change{Kind: Removed, Path: p, Key: attr.Key, Old: attr.Value})
+		}
+	}
+	return changes
+}
+
+// sectionMap returns the sections by path, the first one wins if the path
+// is duplicated.
+func sectionMap(sections []*Section) map[string]*Section {
+	m := make(map[string]*Section, len(sections))
+	for _, s := range sections {
+		if _, dup := m[s.Path]; !dup {
+			m[s.Path] = s
+		}
+	}
+	return m
+}
+
+// attrValues returns the copies of attributes with keys and values only.
+func attrValues(attrs []*Attr) []Attr {
+	if len(attrs) == 0 {
+		return nil
+	}
+	values := make([]Attr, len(attrs))
+	for i, a := range attrs {
+		values[i] = Attr{Key: a.Key, Value: a.Value}
+	}
+	return values
+}
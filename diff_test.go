@@ -0,0 +1,66 @@
+package siebns
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiff(t *testing.T) {
+	const ent = "/enterprises/SBA_84"
+	old, err := ParseBytes([]byte(testfileServers))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// reordered, reformatted and edited copy
+	newContents := strings.Replace(testfileServers, "\tType=integer\n\tValue=200\n", "\tValue=300\n\tType=integer\n\tDescription=\"Max\"\n", 1)
+	newContents = strings.Replace(newContents, "[/enterprises/SBA_84]\n\tPersistence=full\n", "[/enterprises/SBA_84]\r\n", 1)
+	newContents = strings.Replace(newContents, "DAMAAAAAAAA=", "AAAAAAAAAww=", 1)
+	newContents = strings.Replace(newContents, "[/enterprises/SBA_84/servers/app02]", "[/enterprises/SBA_84/servers/app03]", 1)
+	newContents += "[/enterprises/SBA_84/servers/app02]\n\tPersistence=full\n\tType=empty\n"
+	edited, err := ParseBytes([]byte(newContents))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Change{
+		{Kind: Removed, Path: ent, Key: "Persistence", Old: "full"},
+		{Kind: Changed, Path: ent + "/servers/app01/parameters/MaxTasks", Key: "Value", Old: "200", New: "300"},
+		{Kind: Added, Path: ent + "/servers/app01/parameters/MaxTasks", Key: "Description", New: `"Max"`},
+		{Kind: Added, Path: ent + "/servers/app03", Attrs: []Attr{{Key: "Persistence", Value: "full"}, {Key: "Type", Value: "empty"}}},
+	}
+	got := Diff(old, edited)
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(Attr{})); diff != "" {
+		t.Errorf("Diff() mismatch, (-want,+got):\n%s", diff)
+	}
+
+	if got := Diff(old, old); got != nil {
+		t.Errorf("Diff() of the same file = %v, want none", got)
+	}
+
+	reverse := Diff(edited, old)
+	if len(reverse) != len(want) || reverse[0].Kind != Added || reverse[3].Kind != Removed {
+		t.Errorf("Diff() reverse = %v", reverse)
+	}
+}
+
+func TestChange_JSON(t *testing.T) {
+	data, err := json.Marshal([]Change{
+		{Kind: Changed, Path: "/p", Key: "Value", Old: "1", New: "2"},
+		{Kind: Removed, Path: "/q", Attrs: []Attr{{Key: "Type", Value: "empty"}}},
+		{Kind: Changed, Path: "/p", Key: "Label", Old: "", New: "x"},
+		{Kind: Added, Path: "/p", Key: "Description"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"kind":"changed","path":"/p","key":"Value","old":"1","new":"2"},` +
+		`{"kind":"removed","path":"/q","old":"","new":"","attrs":[{"key":"Type","value":"empty"}]},` +
+		`{"kind":"changed","path":"/p","key":"Label","old":"","new":"x"},` +
+		`{"kind":"added","path":"/p","key":"Description","old":"","new":""}]`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+}
@@ -0,0 +1,275 @@
+package siebns
+
+// Entity is the named object of the gateway configuration, see "Layout of
+// the gateway configuration".
+type Entity struct {
+	Name    string
+	Path    string   // section path
+	Section *Section // nil if the file has no section for the object
+	Params  []Param  // parameters set for the object, in the order of the file
+}
+
+// Param returns the parameter of the object with the alias, ok is false if
+// the parameter is not set for the object.
+func (e *Entity) Param(alias string) (prm Param, ok bool) {
+	for _, prm := range e.Params {
+		if prm.Alias == alias {
+			return prm, true
+		}
+	}
+	return Param{}, false
+}
+
+// Enterprise is the Siebel enterprise with its objects.
+type Enterprise struct {
+	Entity
+	Servers         []*Server
+	Components      []*Component // components of the enterprise
+	ComponentGroups []*ComponentGroup
+	NamedSubsystems []*NamedSubsystem
+	Definitions     []*ComponentDefinition
+}
+
+// Server is the Siebel server of the enterprise.
+type Server struct {
+	Entity
+	Enterprise      string
+	Components      []*Component // components of the server
+	ComponentGroups []string     // names of the component groups assigned to the server
+}
+
+// Component is the component of the enterprise or of the server.
+type Component struct {
+	Entity
+	Enterprise string
+	Server     string // empty for the component of the enterprise
+}
+
+// ComponentGroup is the component group of the enterprise.
+type ComponentGroup struct {
+	Entity
+	Enterprise string
+}
+
+// NamedSubsystem is the named subsystem of the enterprise.
+type NamedSubsystem struct {
+	Entity
+	Enterprise string
+}
+
+// ComponentDefinition is the component definition of the enterprise.
+type ComponentDefinition struct {
+	Entity
+	Enterprise string
+}
+
+// Enterprises returns the enterprises in the order of the file.  Like
+// Tree, the result is a snapshot: it is not updated when the sections
+// change.
+func (ns *NSFile) Enterprises() []*Enterprise {
+	var ents []*Enterprise
+	for _, n := range ns.objectNodes(joinPath("/", dirEnterprises)) {
+		ents = append(ents, newEnterprise(n))
+	}
+	return ents
+}
+
+// Enterprise returns the enterprise with the name, or nil if there's none.
+func (ns *NSFile) Enterprise(name string) *Enterprise {
+	n := ns.objectNode(enterprisePath(name))
+	if n == nil {
+		return nil
+	}
+	return newEnterprise(n)
+}
+
+// Servers returns the servers of the enterprise.
+func (ns *NSFile) Servers(enterprise string) []*Server {
+	var srvs []*Server
+	for _, n := range ns.objectNodes(joinPath(enterprisePath(enterprise), dirServers)) {
+		srvs = append(srvs, newServer(enterprise, n))
+	}
+	return srvs
+}
+
+// Server returns the server of the enterprise, or nil if there's none.
+func (ns *NSFile) Server(enterprise, name string) *Server {
+	n := ns.objectNode(serverPath(enterprise, name))
+	if n == nil {
+		return nil
+	}
+	return newServer(enterprise, n)
+}
+
+// Components returns the components of the server, or the components of
+// the enterprise if server is empty.
+func (ns *NSFile) Components(enterprise, server string) []*Component {
+	var comps []*Component
+	for _, n := range ns.objectNodes(componentsPath(enterprise, server)) {
+		comps = append(comps, newComponent(enterprise, server, n))
+	}
+	return comps
+}
+
+// Component returns the component of the server, or of the enterprise if
+// server is empty, or nil if there's none.
+func (ns *NSFile) Component(enterprise, server, name string) *Component {
+	n := ns.objectNode(joinPath(componentsPath(enterprise, server), name))
+	if n == nil {
+		return nil
+	}
+	return newComponent(enterprise, server, n)
+}
+
+// ComponentGroups returns the component groups of the enterprise.
+func (ns *NSFile) ComponentGroups(enterprise string) []*ComponentGroup {
+	var groups []*ComponentGroup
+	for _, n := range ns.objectNodes(joinPath(enterprisePath(enterprise), dirComponentGroups)) {
+		groups = append(groups, &ComponentGroup{Entity: newEntity(n), Enterprise: enterprise})
+	}
+	return groups
+}
+
+// ComponentGroup returns the component group of the enterprise, or nil if
+// there's none.
+func (ns *NSFile) ComponentGroup(enterprise, name string) *ComponentGroup {
+	n := ns.objectNode(joinPath(joinPath(enterprisePath(enterprise), dirComponentGroups), name))
+	if n == nil {
+		return nil
+	}
+	return &ComponentGroup{Entity: newEntity(n), Enterprise: enterprise}
+}
+
+// NamedSubsystems returns the named subsystems of the enterprise.
+func (ns *NSFile) NamedSubsystems(enterprise string) []*NamedSubsystem {
+	var subs []*NamedSubsystem
+	for _, n := range ns.objectNodes(joinPath(enterprisePath(enterprise), dirNamedSubsystems)) {
+		subs = append(subs, &NamedSubsystem{Entity: newEntity(n), Enterprise: enterprise})
+	}
+	return subs
+}
+
+// NamedSubsystem returns the named subsystem of the enterprise, or nil if
+// there's none.
+func (ns *NSFile) NamedSubsystem(enterprise, name string) *NamedSubsystem {
+	n := ns.objectNode(joinPath(joinPath(enterprisePath(enterprise), dirNamedSubsystems), name))
+	if n == nil {
+		return nil
+	}
+	return &NamedSubsystem{Entity: newEntity(n), Enterprise: enterprise}
+}
+
+// ComponentDefinitions returns the component definitions of the
+// enterprise.
+func (ns *NSFile) ComponentDefinitions(enterprise string) []*ComponentDefinition {
+	var defs []*ComponentDefinition
+	for _, n := range ns.objectNodes(joinPath(enterprisePath(enterprise), dirDefinitions)) {
+		defs = append(defs, &ComponentDefinition{Entity: newEntity(n), Enterprise: enterprise})
+	}
+	return defs
+}
+
+// ComponentDefinition returns the component definition of the enterprise,
+// or nil if there's none.
+func (ns *NSFile) ComponentDefinition(enterprise, name string) *ComponentDefinition {
+	n := ns.objectNode(joinPath(joinPath(enterprisePath(enterprise), dirDefinitions), name))
+	if n == nil {
+		return nil
+	}
+	return &ComponentDefinition{Entity: newEntity(n), Enterprise: enterprise}
+}
+
+// objectNode returns the node of the object with the path p, or nil if
+// there's none.
+func (ns *NSFile) objectNode(p string) *Node {
+	return ns.Tree().Get(p)
+}
+
+// objectNodes returns the child nodes of the node with the path p, that
+// are the objects of the same kind, i.e. the servers of the enterprise.
+func (ns *NSFile) objectNodes(p string) []*Node {
+	n := ns.Tree().Get(p)
+	if n == nil {
+		return nil
+	}
+	return n.Children()
+}
+
+// enterprisePath returns the path of the enterprise section.
+func enterprisePath(enterprise string) string {
+	return joinPath(joinPath("/", dirEnterprises), enterprise)
+}
+
+// serverPath returns the path of the server section.
+func serverPath(enterprise, server string) string {
+	return joinPath(joinPath(enterprisePath(enterprise), dirServers), server)
+}
+
+// componentsPath returns the path of the components section of the server,
+// or of the enterprise if server is empty.
+func componentsPath(enterprise, server string) string {
+	if server == "" {
+		return joinPath(enterprisePath(enterprise), dirComponents)
+	}
+	return joinPath(serverPath(enterprise, server), dirComponents)
+}
+
+// newEntity returns the object of the node n with its parameters.
+func newEntity(n *Node) Entity {
+	e := Entity{Name: n.Name(), Path: n.Path(), Section: n.Section()}
+	if params := n.child(dirParameters, false); params != nil {
+		for _, c := range params.Children() {
+			if c.Section() == nil {
+				continue
+			}
+			if loc, ok := parseParamPath(c.Path()); ok {
+				e.Params = append(e.Params, newParam(c.Section(), loc))
+			}
+		}
+	}
+	return e
+}
+
+func newEnterprise(n *Node) *Enterprise {
+	ent := &Enterprise{Entity: newEntity(n)}
+	name := ent.Name
+	for _, c := range children(n, dirServers) {
+		ent.Servers = append(ent.Servers, newServer(name, c))
+	}
+	for _, c := range children(n, dirComponents) {
+		ent.Components = append(ent.Components, newComponent(name, "", c))
+	}
+	for _, c := range children(n, dirComponentGroups) {
+		ent.ComponentGroups = append(ent.ComponentGroups, &ComponentGroup{Entity: newEntity(c), Enterprise: name})
+	}
+	for _, c := range children(n, dirNamedSubsystems) {
+		ent.NamedSubsystems = append(ent.NamedSubsystems, &NamedSubsystem{Entity: newEntity(c), Enterprise: name})
+	}
+	for _, c := range children(n, dirDefinitions) {
+		ent.Definitions = append(ent.Definitions, &ComponentDefinition{Entity: newEntity(c), Enterprise: name})
+	}
+	return ent
+}
+
+func newServer(enterprise string, n *Node) *Server {
+	srv := &Server{Entity: newEntity(n), Enterprise: enterprise}
+	for _, c := range children(n, dirComponents) {
+		srv.Components = append(srv.Components, newComponent(enterprise, srv.Name, c))
+	}
+	for _, c := range children(n, dirComponentGroups) {
+		srv.ComponentGroups = append(srv.ComponentGroups, c.Name())
+	}
+	return srv
+}
+
+func newComponent(enterprise, server string, n *Node) *Component {
+	return &Component{Entity: newEntity(n), Enterprise: enterprise, Server: server}
+}
+
+// children returns the children of the child node name of n.
+func children(n *Node, name string) []*Node {
+	if c := n.child(name, false); c != nil {
+		return c.Children()
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package siebns
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// sameSectionPath compares sections by path.
+var sameSectionPath = cmp.Comparer(func(a, b *Section) bool {
+	return a == b || (a != nil && b != nil && a.Path == b.Path)
+})
+
+func TestNSFile_Enterprises(t *testing.T) {
+	ns, err := ParseBytes([]byte(testfileEnterprise))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const ent = "/enterprises/SBA_84"
+	params := ns.Params()
+	newEnt := func(p string, params ...Param) Entity {
+		elems := splitPath(p)
+		return Entity{Name: elems[len(elems)-1], Path: p, Section: ns.Section(p), Params: params}
+	}
+	app01 := &Server{
+		Entity:     newEnt(ent+"/servers/app01", params[5], params[6]),
+		Enterprise: "SBA_84",
+		Components: []*Component{
+			{Entity: newEnt(ent+"/servers/app01/components/SCCObjMgr_enu", params[7]), Enterprise: "SBA_84", Server: "app01"},
+		},
+		ComponentGroups: []string{"CallCenter"},
+	}
+	app02 := &Server{Entity: newEnt(ent+"/servers/app02", params[8]), Enterprise: "SBA_84"}
+	want := []*Enterprise{{
+		Entity:  newEnt(ent, params[0], params[1]),
+		Servers: []*Server{app01, app02},
+		ComponentGroups: []*ComponentGroup{
+			{Entity: newEnt(ent + "/component groups/CallCenter"), Enterprise: "SBA_84"},
+		},
+		NamedSubsystems: []*NamedSubsystem{
+			{Entity: newEnt(ent+"/named subsystems/GatewayDataSrc", params[4]), Enterprise: "SBA_84"},
+		},
+		Definitions: []*ComponentDefinition{
+			{Entity: newEnt(ent+"/component definitions/SCCObjMgr_enu", params[2], params[3]), Enterprise: "SBA_84"},
+		},
+	}}
+	if diff := cmp.Diff(want, ns.Enterprises(), sameSectionPath); diff != "" {
+		t.Errorf("Enterprises() mismatch, (-want,+got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(want[0], ns.Enterprise("SBA_84"), sameSectionPath); diff != "" {
+		t.Errorf("Enterprise() mismatch, (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want[0].Servers, ns.Servers("SBA_84"), sameSectionPath); diff != "" {
+		t.Errorf("Servers() mismatch, (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff(app02, ns.Server("SBA_84", "app02"), sameSectionPath); diff != "" {
+		t.Errorf("Server() mismatch, (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff(app01.Components[0], ns.Component("SBA_84", "app01", "SCCObjMgr_enu"), sameSectionPath); diff != "" {
+		t.Errorf("Component() mismatch, (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want[0].ComponentGroups[0], ns.ComponentGroup("SBA_84", "CallCenter"), sameSectionPath); diff != "" {
+		t.Errorf("ComponentGroup() mismatch, (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want[0].NamedSubsystems[0], ns.NamedSubsystem("SBA_84", "GatewayDataSrc"), sameSectionPath); diff != "" {
+		t.Errorf("NamedSubsystem() mismatch, (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want[0].Definitions[0], ns.ComponentDefinition("SBA_84", "SCCObjMgr_enu"), sameSectionPath); diff != "" {
+		t.Errorf("ComponentDefinition() mismatch, (-want,+got):\n%s", diff)
+	}
+
+	if prm, ok := app01.Param("MaxTasks"); !ok || prm.Value != "100" {
+		t.Errorf("Param() = %v, %v, want MaxTasks=100", prm, ok)
+	}
+
+	// lookups of missing objects.
+	if e := ns.Enterprise("SBA_82"); e != nil {
+		t.Errorf("Enterprise(missing) = %v, want nil", e)
+	}
+	if s := ns.Server("SBA_84", "app03"); s != nil {
+		t.Errorf("Server(missing) = %v, want nil", s)
+	}
+	if c := ns.Component("SBA_84", "", "SCCObjMgr_enu"); c != nil {
+		t.Errorf("Component(missing) = %v, want nil", c)
+	}
+	if c := ns.Components("SBA_82", ""); c != nil {
+		t.Errorf("Components(missing) = %v, want nil", c)
+	}
+}
@@ -0,0 +1,262 @@
+package siebns
+
+import (
+	"path"
+	"strings"
+)
+
+// SetParam sets the attribute key of the section to value and saves the
+// file.  The attribute is appended to the section if it does not exist.
+// The value is written as is, use the typed setters of Section to have it
+// quoted according to the Type of the section.
+func (ns *NSFile) SetParam(sectionPath, key, value string) error {
+	return ns.edit(func() error { return ns.body.setParam(sectionPath, key, value) })
+}
+
+// DeleteParam removes the attribute key from the section and saves the
+// file.
+func (ns *NSFile) DeleteParam(sectionPath, key string) error {
+	return ns.edit(func() error { return ns.body.deleteParam(sectionPath, key) })
+}
+
+// AddSection adds the section with attributes to the file and saves it.
+// The parent section must exist, the new section is placed after the last
+// descendant of the parent.
+func (ns *NSFile) AddSection(sectionPath string, attrs ...Attr) error {
+	return ns.edit(func() error { return ns.body.addSection(sectionPath, attrs) })
+}
+
+// DeleteSection removes the section that has no child sections from the
+// file and saves it.  Use DeleteSubtree to remove the section with
+// children.
+func (ns *NSFile) DeleteSection(sectionPath string) error {
+	return ns.edit(func() error { return ns.body.deleteSection(sectionPath) })
+}
+
+// DeleteSubtree removes the section and all its descendants from the file
+// and saves it.
+func (ns *NSFile) DeleteSubtree(sectionPath string) error {
+	return ns.edit(func() error { return ns.body.deleteSubtree(sectionPath) })
+}
+
+// RenameSection changes the path of the section and all its descendants
+// and saves the file.  The sections keep their position in the file.
+func (ns *NSFile) RenameSection(oldPath, newPath string) error {
+	return ns.edit(func() error { return ns.body.renameSection(oldPath, newPath) })
+}
+
+// edit applies the change to the sections and saves the file.  If the
+// change or saving fails, the sections and the header are restored, so that
+// the failed change is not written by the next Save.
+func (ns *NSFile) edit(change func() error) error {
+	if err := ns.checkWritable(); err != nil {
+		return err
+	}
+	if ns.bodyErr != nil {
+		return ns.bodyErr
+	}
+	if ns.header == nil {
+		return errNotInitialised
+	}
+	b, hdr := ns.body.clone(), *ns.header
+	err := change()
+	if err == nil {
+		err = ns.Save()
+	}
+	if err != nil {
+		ns.body, *ns.header = b, hdr
+		return err
+	}
+	return nil
+}
+
+// inSubtree returns true if p is root or one of its descendants.
+func inSubtree(p, root string) bool {
+	return p == root || strings.HasPrefix(p, strings.TrimSuffix(root, "/")+"/")
+}
+
+// checkPath returns an error if p is not a valid absolute section path.
+func checkPath(p string) error {
+	if !strings.HasPrefix(p, "/") || path.Clean(p) != p {
+		return &sectionError{p, errInvalidPath}
+	}
+	return nil
+}
+
+// index returns the index of the section p, or -1 if there's none.
+func (b *body) index(p string) int {
+	for i, s := range b.sections {
+		if s.Path == p {
+			return i
+		}
+	}
+	return -1
+}
+
+// section returns the section p, or an error if there's none.
+func (b *body) section(p string) (*Section, error) {
+	i := b.index(p)
+	if i < 0 {
+		return nil, &sectionError{p, errSectionNotFound}
+	}
+	return b.sections[i], nil
+}
+
+func (b *body) setParam(p, key, value string) error {
+	s, err := b.section(p)
+	if err != nil {
+		return err
+	}
+	s.Set(key, value)
+	return nil
+}
+
+func (b *body) deleteParam(p, key string) error {
+	s, err := b.section(p)
+	if err != nil {
+		return err
+	}
+	for i, a := range s.Attrs {
+		if a.Key == key {
+			s.Attrs = append(s.Attrs[:i], s.Attrs[i+1:]...)
+			return nil
+		}
+	}
+	return &sectionError{p, errAttrNotFound}
+}
+
+func (b *body) addSection(p string, attrs []Attr) error {
+	if err := checkPath(p); err != nil {
+		return err
+	}
+	if b.index(p) >= 0 {
+		return &sectionError{p, errSectionExists}
+	}
+	// the new section goes after the last section of the parent subtree,
+	// the root section goes first.
+	pos := 0
+	if p != "/" {
+		parent := path.Dir(p)
+		if b.index(parent) < 0 {
+			return &sectionError{p, errNoParent}
+		}
+		for i, s := range b.sections {
+			if inSubtree(s.Path, parent) {
+				pos = i + 1
+			}
+		}
+	}
+	s := &Section{Path: p}
+	for i := range attrs {
+		s.Attrs = append(s.Attrs, &Attr{Key: attrs[i].Key, Value: attrs[i].Value})
+	}
+	b.sections = append(b.sections, nil)
+	copy(b.sections[pos+1:], b.sections[pos:])
+	b.sections[pos] = s
+	return nil
+}
+
+func (b *body) deleteSection(p string) error {
+	i := b.index(p)
+	if i < 0 {
+		return &sectionError{p, errSectionNotFound}
+	}
+	for _, s := range b.sections {
+		if s.Path != p && inSubtree(s.Path, p) {
+			return &sectionError{p, errHasChildren}
+		}
+	}
+	b.sections = append(b.sections[:i], b.sections[i+1:]...)
+	return nil
+}
+
+func (b *body) deleteSubtree(p string) error {
+	if b.index(p) < 0 {
+		return &sectionError{p, errSectionNotFound}
+	}
+	var kept []*Section
+	for _, s := range b.sections {
+		if !inSubtree(s.Path, p) {
+			kept = append(kept, s)
+		}
+	}
+	b.sections = kept
+	return nil
+}
+
+func (b *body) renameSection(oldPath, newPath string) error {
+	if err := checkPath(newPath); err != nil {
+		return err
+	}
+	if b.index(oldPath) < 0 {
+		return &sectionError{oldPath, errSectionNotFound}
+	}
+	if oldPath == "/" || inSubtree(newPath, oldPath) {
+		return &sectionError{newPath, errInvalidPath}
+	}
+	for _, s := range b.sections {
+		if inSubtree(s.Path, newPath) {
+			return &sectionError{newPath, errSectionExists}
+		}
+	}
+	if b.index(path.Dir(newPath)) < 0 {
+		return &sectionError{newPath, errNoParent}
+	}
+	for _, s := range b.sections {
+		if inSubtree(s.Path, oldPath) {
+			s.Path = newPath + strings.TrimPrefix(s.Path, oldPath)
+		}
+	}
+	return nil
+}
+
+// copySubtree copies the section src and all its descendants to dst, that
+// must not exist.  The copies are placed after the last section of the
+// parent subtree of dst, in the order of the originals.  If value is not
+// nil, it returns the value of the Value attribute of the copy.
+func (b *body) copySubtree(src, dst string, value func(string) string) error {
+	if err := checkPath(dst); err != nil {
+		return err
+	}
+	if b.index(src) < 0 {
+		return &sectionError{src, errSectionNotFound}
+	}
+	if src == "/" || inSubtree(dst, src) {
+		return &sectionError{dst, errInvalidPath}
+	}
+	for _, s := range b.sections {
+		if inSubtree(s.Path, dst) {
+			return &sectionError{dst, errSectionExists}
+		}
+	}
+	parent := path.Dir(dst)
+	if b.index(parent) < 0 {
+		return &sectionError{dst, errNoParent}
+	}
+	var copies []*Section
+	for _, s := range b.sections {
+		if !inSubtree(s.Path, src) {
+			continue
+		}
+		c := &Section{Path: dst + strings.TrimPrefix(s.Path, src)}
+		for _, a := range s.Attrs {
+			v := a.Value
+			if a.Key == keyValue && value != nil {
+				v = value(v)
+			}
+			c.Attrs = append(c.Attrs, &Attr{Key: a.Key, Value: v})
+		}
+		copies = append(copies, c)
+	}
+	pos := 0
+	for i, s := range b.sections {
+		if inSubtree(s.Path, parent) {
+			pos = i + 1
+		}
+	}
+	sections := make([]*Section, 0, len(b.sections)+len(copies))
+	sections = append(sections, b.sections[:pos]...)
+	sections = append(sections, copies...)
+	b.sections = append(sections, b.sections[pos:]...)
+	return nil
+}
@@ -0,0 +1,243 @@
+package siebns
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// sectionPaths returns the paths of the sections.
+func sectionPaths(sections []*Section) []string {
+	var paths []string
+	for _, s := range sections {
+		paths = append(paths, s.Path)
+	}
+	return paths
+}
+
+func Test_body_edit(t *testing.T) {
+	const (
+		ent     = "/enterprises/SBA_84"
+		servers = ent + "/servers"
+	)
+	tests := []struct {
+		name      string
+		edit      func(b *body) error
+		wantPaths []string // sections under servers
+		wantErr   error
+	}{
+		{"add section",
+			func(b *body) error { return b.addSection(servers+"/app01/parameters", nil) },
+			[]string{servers, servers + "/app01", servers + "/app01/parameters/MaxTasks", servers + "/app01/parameters", servers + "/app02", servers + "/app02/parameters", servers + "/app02/parameters/MaxTasks"},
+			nil},
+		{"add section at the end of the parent subtree",
+			func(b *body) error { return b.addSection(servers+"/app03", nil) },
+			[]string{servers, servers + "/app01", servers + "/app01/parameters/MaxTasks", servers + "/app02", servers + "/app02/parameters", servers + "/app02/parameters/MaxTasks", servers + "/app03"},
+			nil},
+		{"add existing section",
+			func(b *body) error { return b.addSection(servers+"/app01", nil) },
+			nil, errSectionExists},
+		{"add section without parent",
+			func(b *body) error { return b.addSection(servers+"/app03/parameters", nil) },
+			nil, errNoParent},
+		{"add invalid path",
+			func(b *body) error { return b.addSection(servers+"/app03/", nil) },
+			nil, errInvalidPath},
+		{"delete section",
+			func(b *body) error { return b.deleteSection(servers + "/app02/parameters/MaxTasks") },
+			[]string{servers, servers + "/app01", servers + "/app01/parameters/MaxTasks", servers + "/app02", servers + "/app02/parameters"},
+			nil},
+		{"delete section with children",
+			func(b *body) error { return b.deleteSection(servers + "/app01") },
+			nil, errHasChildren},
+		{"delete missing section",
+			func(b *body) error { return b.deleteSection(servers + "/app03") },
+			nil, errSectionNotFound},
+		{"delete subtree",
+			func(b *body) error { return b.deleteSubtree(servers + "/app01") },
+			[]string{servers, servers + "/app02", servers + "/app02/parameters", servers + "/app02/parameters/MaxTasks"},
+			nil},
+		{"delete subtree does not touch siblings with the same prefix",
+			func(b *body) error {
+				if err := b.addSection(servers+"/app011", nil); err != nil {
+					return err
+				}
+				return b.deleteSubtree(servers + "/app01")
+			},
+			[]string{servers, servers + "/app02", servers + "/app02/parameters", servers + "/app02/parameters/MaxTasks", servers + "/app011"},
+			nil},
+		{"rename section",
+			func(b *body) error { return b.renameSection(servers+"/app01", servers+"/web01") },
+			[]string{servers, servers + "/web01", servers + "/web01/parameters/MaxTasks", servers + "/app02", servers + "/app02/parameters", servers + "/app02/parameters/MaxTasks"},
+			nil},
+		{"rename to existing",
+			func(b *body) error { return b.renameSection(servers+"/app01", servers+"/app02") },
+			nil, errSectionExists},
+		{"rename into itself",
+			func(b *body) error { return b.renameSection(servers+"/app01", servers+"/app01/x") },
+			nil, errInvalidPath},
+		{"rename without parent",
+			func(b *body) error { return b.renameSection(servers+"/app01", ent+"/hosts/app01") },
+			nil, errNoParent},
+		{"rename missing",
+			func(b *body) error { return b.renameSection(servers+"/app03", servers+"/app04") },
+			nil, errSectionNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := parseTestNSFile(t, testfileServers)
+			defer CloseTestNSFile(ns)
+
+			err := tt.edit(&ns.body)
+			if sectionErr(err) != tt.wantErr {
+				t.Fatalf("edit error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var got []string
+			for _, p := range sectionPaths(ns.sections) {
+				if inSubtree(p, servers) {
+					got = append(got, p)
+				}
+			}
+			if diff := cmp.Diff(tt.wantPaths, got); diff != "" {
+				t.Errorf("paths mismatch, (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_body_params(t *testing.T) {
+	ns := parseTestNSFile(t, testfileSample)
+	defer CloseTestNSFile(ns)
+
+	const p = "/enterprises/SBA_84/parameters/MaxTasks"
+	if err := ns.body.setParam(p, "Value", "200"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ns.body.setParam(p, "Description", `"Max Tasks"`); err != nil {
+		t.Fatal(err)
+	}
+	if err := ns.body.deleteParam(p, "Persistence"); err != nil {
+		t.Fatal(err)
+	}
+	want := []*Attr{{Key: "Type", Value: "integer"}, {Key: "Value", Value: "200"}, {Key: "Description", Value: `"Max Tasks"`}}
+	if diff := cmp.Diff(want, ns.Section(p).Attrs, cmp.Comparer(func(a, b *Attr) bool { return a.Key == b.Key && a.Value == b.Value })); diff != "" {
+		t.Errorf("Attrs mismatch, (-want,+got):\n%s", diff)
+	}
+	if err := ns.body.deleteParam(p, "Persistence"); sectionErr(err) != errAttrNotFound {
+		t.Errorf("deleteParam() error = %v, want %v", err, errAttrNotFound)
+	}
+	if err := ns.body.setParam("/nonexistent", "Value", "1"); sectionErr(err) != errSectionNotFound {
+		t.Errorf("setParam() error = %v, want %v", err, errSectionNotFound)
+	}
+}
+
+func TestNSFile_EditSaves(t *testing.T) {
+	ns := parseTestNSFile(t, withValidSize(testfileSample))
+	defer CloseTestNSFile(ns)
+
+	if err := ns.AddSection("/enterprises/SBA_84/servers", Attr{Key: "Persistence", Value: "full"}, Attr{Key: "Type", Value: "empty"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ns.SetParam("/enterprises/SBA_84/parameters/MaxTasks", "Value", "200"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ns.DeleteSubtree("/enterprises/SBA_84/parameters"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ns.DeleteSection("/enterprises/SBA_84/parameters"); sectionErr(err) != errSectionNotFound {
+		t.Errorf("DeleteSection() error = %v, want %v", err, errSectionNotFound)
+	}
+
+	got, err := ioutil.ReadFile(ns.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := withValidSize(`Siebel Name Server Backing File
+16.0.0.0 [23057] ENU
+1.2
+DAMAAAAAAAA=             
+
+[/]
+	Persistence=partial
+	Type=empty
+[/enterprises]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84]
+	Persistence=full
+	Type=string
+	Value="Siebel Enterprise"
+[/enterprises/SBA_84/servers]
+	Persistence=full
+	Type=empty
+`)
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("file mismatch, (-want,+got):\n%s", diff)
+	}
+	if !ns.IsHeaderCorrect() {
+		t.Error("IsHeaderCorrect() = false after save")
+	}
+
+	reread, err := readHeader(bytes.NewReader(got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reread.offsets.checksum != ns.header.offsets.checksum {
+		t.Errorf("checksum offset = %d, want %d", reread.offsets.checksum, ns.header.offsets.checksum)
+	}
+}
+
+func TestNSFile_EditRollback(t *testing.T) {
+	dir, name := createTestDir(t, withValidSize(testfileEnterprise))
+	defer os.RemoveAll(dir)
+
+	errRunning := errors.New("running")
+	check := &fakeWriteChecker{WantError: errRunning}
+	ns, err := Open(name, WithWriteCheck(check), WithBackups(NoBackup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ns.Close()
+
+	const (
+		host  = "/enterprises/SBA_84/servers/app01/parameters/Host"
+		app02 = "/enterprises/SBA_84/servers/app02/parameters/Lang"
+	)
+	if err := ns.DeleteSection(host); err != errRunning {
+		t.Fatalf("DeleteSection() error = %v, want %v", err, errRunning)
+	}
+	if err := ns.CloneServer("SBA_84", "app01", "app02", CloneOptions{Overwrite: true}); err != errRunning {
+		t.Fatalf("CloneServer() error = %v, want %v", err, errRunning)
+	}
+	for _, p := range []string{host, app02} {
+		if ns.Section(p) == nil {
+			t.Errorf("refused change removed %s", p)
+		}
+	}
+
+	// the refused changes are not written by the next change.
+	check.WantError = nil
+	if err := ns.SetParam("/enterprises/SBA_84/parameters/MaxTasks", "Value", "200"); err != nil {
+		t.Fatal(err)
+	}
+	reread, err := OpenReadOnly(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reread.Close()
+	for _, p := range []string{host, app02} {
+		if reread.Section(p) == nil {
+			t.Errorf("saved file has no %s", p)
+		}
+	}
+	if v, _ := reread.Section("/enterprises/SBA_84/parameters/MaxTasks").Get("Value"); v != "200" {
+		t.Errorf("MaxTasks = %s, want 200", v)
+	}
+}
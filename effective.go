@@ -0,0 +1,56 @@
+package siebns
+
+// inheritanceChain returns the locations the parameter alias of the
+// component running on the server inherits its value from, from the lowest
+// priority to the highest:
+//
+//	component definition → enterprise → server → component → server component
+//
+// If server is empty, the server levels are omitted, and if component is
+// empty, the component levels are omitted.
+func inheritanceChain(enterprise, server, component, alias string) []paramLocation {
+	var chain []paramLocation
+	if component != "" {
+		chain = append(chain, paramLocation{enterprise: enterprise, level: LevelDefinition, object: component, alias: alias})
+	}
+	chain = append(chain, paramLocation{enterprise: enterprise, level: LevelEnterprise, alias: alias})
+	if server != "" {
+		chain = append(chain, paramLocation{enterprise: enterprise, server: server, level: LevelServer, alias: alias})
+	}
+	if component != "" {
+		chain = append(chain, paramLocation{enterprise: enterprise, level: LevelComponent, object: component, alias: alias})
+	}
+	if server != "" && component != "" {
+		chain = append(chain, paramLocation{enterprise: enterprise, server: server, level: LevelServerComponent, object: component, alias: alias})
+	}
+	return chain
+}
+
+// EffectiveParam returns the value of the parameter alias in effect for
+// the component running on the server of the enterprise, and the chain of
+// the levels the parameter is set at, from the lowest priority to the
+// highest, see Level.  The effective parameter is the last one of the
+// chain.
+//
+// If component is empty, the parameter in effect for the server is
+// returned, and if server is empty as well, the one of the enterprise.  An
+// error is returned if the parameter is not set at any level.
+func (ns *NSFile) EffectiveParam(enterprise, server, component, alias string) (Param, []Param, error) {
+	chain := resolveChain(ns.Section, enterprise, server, component, alias)
+	if len(chain) == 0 {
+		return Param{}, nil, errParamNotSet
+	}
+	return chain[len(chain)-1], chain, nil
+}
+
+// resolveChain returns the parameters of the inheritance chain that are
+// set, section returns the section with the path or nil if there's none.
+func resolveChain(section func(string) *Section, enterprise, server, component, alias string) []Param {
+	var chain []Param
+	for _, loc := range inheritanceChain(enterprise, server, component, alias) {
+		if s := section(paramPath(loc)); s != nil {
+			chain = append(chain, newParam(s, loc))
+		}
+	}
+	return chain
+}
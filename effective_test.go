@@ -0,0 +1,88 @@
+package siebns
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNSFile_EffectiveParam(t *testing.T) {
+	ns, err := ParseBytes([]byte(testfileEnterprise))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const ent = "/enterprises/SBA_84"
+	type args struct {
+		server    string
+		component string
+		alias     string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantValue string
+		wantChain []string // paths
+		wantErr   error
+	}{
+		{"server component override",
+			args{"app01", "SCCObjMgr_enu", "MaxTasks"},
+			"200",
+			[]string{
+				ent + "/component definitions/SCCObjMgr_enu/parameters/MaxTasks",
+				ent + "/parameters/MaxTasks",
+				ent + "/servers/app01/parameters/MaxTasks",
+				ent + "/servers/app01/components/SCCObjMgr_enu/parameters/MaxTasks",
+			},
+			nil},
+		{"server override",
+			args{"app02", "SCCObjMgr_enu", "Lang"},
+			"DEU",
+			[]string{
+				ent + "/component definitions/SCCObjMgr_enu/parameters/Lang",
+				ent + "/parameters/Lang",
+				ent + "/servers/app02/parameters/Lang",
+			},
+			nil},
+		{"server",
+			args{"app02", "", "MaxTasks"},
+			"100",
+			[]string{ent + "/parameters/MaxTasks"},
+			nil},
+		{"enterprise",
+			args{"", "", "Lang"},
+			"ENU",
+			[]string{ent + "/parameters/Lang"},
+			nil},
+		{"definition only",
+			args{"app02", "SCCObjMgr_enu", "MaxTasks"},
+			"100",
+			[]string{
+				ent + "/component definitions/SCCObjMgr_enu/parameters/MaxTasks",
+				ent + "/parameters/MaxTasks",
+			},
+			nil},
+		{"not set",
+			args{"app01", "SCCObjMgr_enu", "MinMTServers"},
+			"",
+			nil,
+			errParamNotSet},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, chain, err := ns.EffectiveParam("SBA_84", tt.args.server, tt.args.component, tt.args.alias)
+			if err != tt.wantErr {
+				t.Fatalf("EffectiveParam() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Value != tt.wantValue {
+				t.Errorf("EffectiveParam() value = %q, want %q", got.Value, tt.wantValue)
+			}
+			var paths []string
+			for _, prm := range chain {
+				paths = append(paths, prm.Path)
+			}
+			if diff := cmp.Diff(tt.wantChain, paths); diff != "" {
+				t.Errorf("EffectiveParam() chain mismatch, (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package siebns
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Export format
+//
+// ExportJSON and ExportYAML write the file as the object with two fields:
+// "header" and "root".  The header holds the file metadata:
+//
+//	siebel     Siebel version line, i.e. "16.0.0.0 [23057] ENU"
+//	nsfile     nsfile format version, i.e. "1.2"
+//	byteOrder  byte order of the encoded size, "little" or "big"
+//	unicode    true if the file starts with the BOM
+//	dos        true if lines end with CRLF
+//
+// The root is the object of the section "/".  Each node of the section
+// tree is the object with attributes of its section as fields, followed by
+// the "children" field, holding the child nodes by their names.  Fields go
+// in the order of the file.  The Value attribute is typed according to the
+// Type of the section: integers are numbers, booleans are true or false,
+// strings are unquoted.  Values that don't match the Type, and all other
+// attributes are strings, except for the string Value with unbalanced
+// quotes: it can't be told from the unquoted string, and the file with
+// such a value is not exported.  The node that has no section in the file,
+// i.e. "/a" of the file with the section "/a/b" only, has the "section"
+// field set to false instead of attributes.
+//
+//	{
+//	  "header": {"siebel": "16.0.0.0 [23057] ENU", "nsfile": "1.2", ...},
+//	  "root": {
+//	    "Persistence": "partial",
+//	    "Type": "empty",
+//	    "children": {
+//	      "enterprises": {...}
+//	    }
+//	  }
+//	}
+
+// Reserved fields of the exported node, that can't be the attributes.
+const (
+	keyChildren = "children" // child nodes
+	keySection  = "section"  // false if the node has no section
+)
+
+// Fields of the exported file.
+const (
+	fieldHeader    = "header"
+	fieldRoot      = "root"
+	fieldSiebel    = "siebel"
+	fieldNSFile    = "nsfile"
+	fieldByteOrder = "byteOrder"
+	fieldUnicode   = "unicode"
+	fieldDOS       = "dos"
+)
+
+// ExportJSON writes the file to w as indented JSON, see "Export format".
+func (ns *NSFile) ExportJSON(w io.Writer) error {
+	obj, err := ns.export()
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(obj)
+}
+
+// ExportYAML writes the file to w as YAML, see "Export format".
+func (ns *NSFile) ExportYAML(w io.Writer) error {
+	obj, err := ns.export()
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// object is the object with the ordered fields.
+type object yaml.MapSlice
+
+// MarshalJSON implements json.Marshaler, keeping the order of fields.
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, item := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(item.Key)
+		if err != nil {
+			return nil, err
+		}
+		val, err := json.Marshal(item.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalYAML implements yaml.Marshaler, keeping the order of fields.
+func (o object) MarshalYAML() (interface{}, error) {
+	return yaml.MapSlice(o), nil
+}
+
+// export returns the exported file.
+func (ns *NSFile) export() (object, error) {
+	if ns.bodyErr != nil {
+		return nil, ns.bodyErr
+	}
+	ns.applyByteOrder()
+	hdr := ns.header
+	if hdr == nil {
+		return nil, errNotInitialised
+	}
+	if hdr.byteOrder == nil {
+		return nil, errByteOrderNil
+	}
+	root, err := exportNode(ns.Tree())
+	if err != nil {
+		return nil, err
+	}
+	return object{
+		{Key: fieldHeader, Value: object{
+			{Key: fieldSiebel, Value: hdr.version.siebel},
+			{Key: fieldNSFile, Value: hdr.version.nsfile},
+			{Key: fieldByteOrder, Value: byteOrderName(hdr.byteOrder)},
+			{Key: fieldUnicode, Value: hdr.format.unicode},
+			{Key: fieldDOS, Value: hdr.format.dos},
+		}},
+		{Key: fieldRoot, Value: root},
+	}, nil
+}
+
+// exportNode returns the exported node n with its descendants.  Duplicate
+// attributes are skipped, the first one wins.
+func exportNode(n *Node) (object, error) {
+	obj := object{}
+	if n.section == nil {
+		obj = append(obj, yaml.MapItem{Key: keySection, Value: false})
+	}
+	if s := n.section; s != nil {
+		seen := make(map[string]bool, len(s.Attrs))
+		for _, a := range s.Attrs {
+			if seen[a.Key] {
+				continue
+			}
+			seen[a.Key] = true
+			if a.Key == keyChildren || a.Key == keySection {
+				return nil, &sectionError{s.Path, errReservedKey}
+			}
+			v, err := exportValue(s, a)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, yaml.MapItem{Key: a.Key, Value: v})
+		}
+	}
+	if len(n.children) == 0 {
+		return obj, nil
+	}
+	children := make(object, 0, len(n.children))
+	for _, c := range n.children {
+		child, err := exportNode(c)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, yaml.MapItem{Key: c.name, Value: child})
+	}
+	return append(obj, yaml.MapItem{Key: keyChildren, Value: children}), nil
+}
+
+// exportValue returns the value of the attribute, typed if it is the Value
+// of the section.  The string Value that can't be unquoted is an error, as
+// it would be quoted again on import.
+func exportValue(s *Section, a *Attr) (interface{}, error) {
+	if a.Key != keyValue {
+		return a.Value, nil
+	}
+	var (
+		v   interface{}
+		err error
+	)
+	switch s.Type() {
+	case TypeInteger:
+		v, err = s.Int()
+	case TypeBoolean:
+		v, err = s.Bool()
+	case TypeString:
+		if v, err = s.String(); err != nil {
+			return nil, err
+		}
+	default:
+		return a.Value, nil
+	}
+	if err != nil {
+		return a.Value, nil
+	}
+	return v, nil
+}
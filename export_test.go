@@ -0,0 +1,156 @@
+package siebns
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testfileTyped = testfileSample +
+	"[/enterprises/SBA_84/parameters/Compress]\r\n" +
+	"\tType=boolean\r\n" +
+	"\tValue=True\r\n" +
+	"[/enterprises/SBA_84/parameters/Port]\n" +
+	"\tType=integer\n" +
+	"\tValue=abc\n" +
+	"[/enterprises/SBA_84/servers/app01]\n" +
+	"\tType=empty\n"
+
+func TestNSFile_ExportJSON(t *testing.T) {
+	ns, err := ParseBytes([]byte(testfileTyped))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := ns.ExportJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "header": {
+    "siebel": "16.0.0.0 [23057] ENU",
+    "nsfile": "1.2",
+    "byteOrder": "little",
+    "unicode": false,
+    "dos": false
+  },
+  "root": {
+    "Persistence": "partial",
+    "Type": "empty",
+    "children": {
+      "enterprises": {
+        "Persistence": "full",
+        "Type": "empty",
+        "children": {
+          "SBA_84": {
+            "Persistence": "full",
+            "Type": "string",
+            "Value": "Siebel Enterprise",
+            "children": {
+              "parameters": {
+                "Persistence": "full",
+                "Type": "empty",
+                "children": {
+                  "MaxTasks": {
+                    "Persistence": "full",
+                    "Type": "integer",
+                    "Value": 100
+                  },
+                  "Compress": {
+                    "Type": "boolean",
+                    "Value": true
+                  },
+                  "Port": {
+                    "Type": "integer",
+                    "Value": "abc"
+                  }
+                }
+              },
+              "servers": {
+                "section": false,
+                "children": {
+                  "app01": {
+                    "Type": "empty"
+                  }
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("ExportJSON() mismatch, (-want,+got):\n%s", diff)
+	}
+}
+
+func TestNSFile_ExportYAML(t *testing.T) {
+	ns, err := ParseBytes([]byte(strings.Replace(testfileSample, "DAMAAAAAAAA=", "AAAAAAAAAww=", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := ns.ExportYAML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `header:
+  siebel: 16.0.0.0 [23057] ENU
+  nsfile: "1.2"
+  byteOrder: big
+  unicode: false
+  dos: false
+root:
+  Persistence: partial
+  Type: empty
+  children:
+    enterprises:
+      Persistence: full
+      Type: empty
+      children:
+        SBA_84:
+          Persistence: full
+          Type: string
+          Value: Siebel Enterprise
+          children:
+            parameters:
+              Persistence: full
+              Type: empty
+              children:
+                MaxTasks:
+                  Persistence: full
+                  Type: integer
+                  Value: 100
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("ExportYAML() mismatch, (-want,+got):\n%s", diff)
+	}
+}
+
+func TestNSFile_export_reservedKey(t *testing.T) {
+	for _, key := range []string{keyChildren, keySection} {
+		ns, err := ParseBytes([]byte(testfileSample + "\t" + key + "=1\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ns.ExportJSON(&bytes.Buffer{}); sectionErr(err) != errReservedKey {
+			t.Errorf("ExportJSON(%s) error = %v, want %v", key, err, errReservedKey)
+		}
+	}
+}
+
+func TestNSFile_export_unbalancedQuotes(t *testing.T) {
+	ns, err := ParseBytes([]byte(testfileSample +
+		"[/enterprises/SBA_84/parameters/Label]\n" +
+		"\tType=string\n" +
+		"\tValue=\"x\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ns.ExportJSON(&bytes.Buffer{}); sectionErr(err) != errUnbalancedQuotes {
+		t.Errorf("ExportJSON() error = %v, want %v", err, errUnbalancedQuotes)
+	}
+}
@@ -0,0 +1,134 @@
+package siebns
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultGatewayPort is the default port of the Siebel Gateway Name Server.
+const DefaultGatewayPort = 2320
+
+// WriteChecker checks if it is safe to write the file before it is
+// written.
+type WriteChecker interface {
+	// CheckWrite returns an error if the file path must not be written.
+	CheckWrite(path string) error
+}
+
+// GatewayCheck is the WriteChecker that refuses writing while the Siebel
+// Gateway Name Server is running on this host: the gateway rewrites
+// siebns.dat on shutdown, and the changes made while it runs are lost.
+// The gateway is considered running if there's the gateway process, or
+// something listens on the gateway port.  Detection is only implemented
+// for Linux, on other systems the check always passes.
+type GatewayCheck struct {
+	// Port is the gateway port, DefaultGatewayPort if zero.
+	Port int
+}
+
+// DefaultWriteCheck is the WriteChecker installed by Open.
+var DefaultWriteCheck WriteChecker = GatewayCheck{}
+
+// gatewayError is returned when the gateway appears to be running.
+type gatewayError struct {
+	pid  int // pid of the gateway process, 0 if not found
+	port int // listening port, 0 if the port is not bound
+}
+
+func (e *gatewayError) Error() string {
+	if e.pid != 0 {
+		return fmt.Sprintf("%s: process %d", errGatewayRunning, e.pid)
+	}
+	return fmt.Sprintf("%s: port %d is listening", errGatewayRunning, e.port)
+}
+
+// IsGatewayRunning returns true if err is the error of GatewayCheck,
+// reporting that the gateway is running.
+func IsGatewayRunning(err error) bool {
+	_, ok := err.(*gatewayError)
+	return ok
+}
+
+// WithWriteCheck sets the check that is run before the file is written by
+// Save and FixSize.  By default, DefaultWriteCheck is used, pass nil to
+// disable the check.
+func WithWriteCheck(c WriteChecker) Option {
+	return func(ns *NSFile) {
+		ns.writeCheck = c
+	}
+}
+
+// CheckWrite implements WriteChecker.
+func (c GatewayCheck) CheckWrite(path string) error {
+	port := c.Port
+	if port == 0 {
+		port = DefaultGatewayPort
+	}
+	if pid := findGatewayProcess(); pid != 0 {
+		return &gatewayError{pid: pid}
+	}
+	if portListening(port) {
+		return &gatewayError{port: port}
+	}
+	return nil
+}
+
+// checkWrite runs the write check, if any.
+func (ns *NSFile) checkWrite() error {
+	if ns.writeCheck == nil || ns.path == "" {
+		return nil
+	}
+	return ns.writeCheck.CheckWrite(ns.path)
+}
+
+// isGatewayCmdline returns true if the NUL separated command line is the one
+// of the Siebel Gateway Name Server, i.e. "siebsvc -s gtwyns -a ...".
+func isGatewayCmdline(cmdline []byte) bool {
+	args := bytes.Split(bytes.TrimRight(cmdline, "\x00"), []byte{0})
+	if len(args) == 0 {
+		return false
+	}
+	switch strings.ToLower(filepath.Base(string(args[0]))) {
+	case "gtwyns":
+		return true
+	case "siebsvc":
+		for i := 1; i < len(args)-1; i++ {
+			if string(args[i]) == "-s" && strings.EqualFold(string(args[i+1]), "gtwyns") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tcpListen is the state of the listening socket in /proc/net/tcp.
+const tcpListen = "0A"
+
+// listensOn returns true if the table in /proc/net/tcp format has the
+// socket listening on port.
+//
+//	sl  local_address rem_address   st ...
+//	 0: 00000000:0910 00000000:0000 0A ...
+func listensOn(r io.Reader, port int) bool {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 4 || fields[3] != tcpListen {
+			continue
+		}
+		colon := strings.LastIndexByte(fields[1], ':')
+		if colon < 0 {
+			continue
+		}
+		p, err := strconv.ParseUint(fields[1][colon+1:], 16, 16)
+		if err == nil && int(p) == port {
+			return true
+		}
+	}
+	return false
+}
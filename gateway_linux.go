@@ -0,0 +1,45 @@
+package siebns
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// findGatewayProcess returns the pid of the running Siebel Gateway Name
+// Server process, or 0 if there's none.
+func findGatewayProcess() int {
+	cmdlines, err := filepath.Glob("/proc/[0-9]*/cmdline")
+	if err != nil {
+		return 0
+	}
+	for _, name := range cmdlines {
+		cmdline, err := ioutil.ReadFile(name)
+		if err != nil || !isGatewayCmdline(cmdline) {
+			continue
+		}
+		pid, err := strconv.Atoi(filepath.Base(filepath.Dir(name)))
+		if err != nil || pid == os.Getpid() {
+			continue
+		}
+		return pid
+	}
+	return 0
+}
+
+// portListening returns true if there's the TCP socket listening on port.
+func portListening(port int) bool {
+	for _, name := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		f, err := os.Open(name)
+		if err != nil {
+			continue
+		}
+		found := listensOn(f, port)
+		f.Close()
+		if found {
+			return true
+		}
+	}
+	return false
+}
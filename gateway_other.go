@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package siebns
+
+// findGatewayProcess is not implemented on this system.
+func findGatewayProcess() int {
+	return 0
+}
+
+// portListening is not implemented on this system.
+func portListening(port int) bool {
+	return false
+}
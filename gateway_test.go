@@ -0,0 +1,135 @@
+package siebns
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestMain disables the gateway check, so that the tests don't depend on
+// the processes and ports of the host.
+func TestMain(m *testing.M) {
+	DefaultWriteCheck = nil
+	os.Exit(m.Run())
+}
+
+type fakeWriteChecker struct {
+	WantError error
+
+	paths []string
+}
+
+func (c *fakeWriteChecker) CheckWrite(path string) error {
+	c.paths = append(c.paths, path)
+	return c.WantError
+}
+
+func TestWithWriteCheck(t *testing.T) {
+	contents := withValidSize(testfileSample)
+	dir, name := createTestDir(t, contents)
+	defer os.RemoveAll(dir)
+
+	errRunning := errors.New("running")
+	check := &fakeWriteChecker{WantError: errRunning}
+	ns, err := Open(name, WithWriteCheck(check))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ns.Close()
+
+	if err := ns.SetParam("/enterprises/SBA_84/parameters/MaxTasks", "Value", "200"); err != errRunning {
+		t.Errorf("SetParam() error = %v, want %v", err, errRunning)
+	}
+	if _, err := ns.FixSize(); err != errRunning {
+		t.Errorf("FixSize() error = %v, want %v", err, errRunning)
+	}
+	if diff := cmp.Diff([]string{name, name}, check.paths); diff != "" {
+		t.Errorf("checked paths mismatch, (-want,+got):\n%s", diff)
+	}
+	got, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != contents {
+		t.Error("file was modified")
+	}
+
+	check.WantError = nil
+	if err := ns.Save(); err != nil {
+		t.Errorf("Save() error = %v", err)
+	}
+}
+
+func TestIsGatewayRunning(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&gatewayError{pid: 42}, true},
+		{&gatewayError{port: DefaultGatewayPort}, true},
+		{errGatewayRunning, false},
+		{errors.New("running"), false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := IsGatewayRunning(tt.err); got != tt.want {
+			t.Errorf("IsGatewayRunning(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func Test_isGatewayCmdline(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmdline string
+		want    bool
+	}{
+		{"siebsvc gtwyns", "/siebel/gtwysrvr/bin/siebsvc\x00-s\x00gtwyns\x00-a\x00-g\x00host:2320\x00", true},
+		{"siebsvc GTWYNS", "siebsvc\x00-s\x00GTWYNS\x00", true},
+		{"gtwyns binary", "/siebel/gtwysrvr/bin/gtwyns\x00", true},
+		{"siebel server", "/siebel/siebsrvr/bin/siebsvc\x00-s\x00siebsrvr\x00-a\x00", false},
+		{"gtwyns as argument", "vi\x00gtwyns\x00", false},
+		{"-s last", "siebsvc\x00-s\x00", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isGatewayCmdline([]byte(tt.cmdline)); got != tt.want {
+				t.Errorf("isGatewayCmdline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_listensOn(t *testing.T) {
+	const table = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:0910 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 12345 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:0016 0100007F:A2B4 01 00000000:00000000 00:00000000 00000000     0        0 23456 1 0000000000000000 20 4 30 10 -1
+`
+	const table6 = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000000000000:0910 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 34567 1 0000000000000000 100 0 0 10 0
+`
+	tests := []struct {
+		name  string
+		table string
+		port  int
+		want  bool
+	}{
+		{"listening", table, 2320, true},
+		{"established only", table, 22, false},
+		{"not found", table, 8080, false},
+		{"ipv6", table6, 2320, true},
+		{"empty", "", 2320, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := listensOn(strings.NewReader(tt.table), tt.port); got != tt.want {
+				t.Errorf("listensOn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
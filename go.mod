@@ -2,4 +2,7 @@ module github.com/rusq/siebns
 
 go 1.12
 
-require github.com/google/go-cmp v0.2.0
+require (
+	github.com/google/go-cmp v0.2.0
+	gopkg.in/yaml.v2 v2.4.0
+)
@@ -0,0 +1,137 @@
+package siebns
+
+import (
+	"encoding/binary"
+	"strconv"
+	"strings"
+)
+
+// Header is the header of the file.
+type Header struct {
+	Siebel    SiebelVersion    // version of Siebel that wrote the file
+	Format    Version          // nsfile format version, i.e. 1.2
+	ByteOrder binary.ByteOrder // byte order of the encoded size
+	Unicode   bool             // file starts with the BOM
+	DOS       bool             // lines end with CRLF
+}
+
+// SiebelVersion is the version line of the header, i.e.
+// "16.0.0.0 [23057] ENU".
+type SiebelVersion struct {
+	Version  Version // 16.0.0.0
+	Build    int     // 23057
+	Language string  // ENU
+	Raw      string  // the line as it is in the file
+}
+
+func (v SiebelVersion) String() string {
+	return v.Raw
+}
+
+// Version is the dotted version number, i.e. 16.0.0.0 or 1.2.
+type Version []int
+
+// ParseVersion parses the dotted version number.
+func ParseVersion(s string) (Version, error) {
+	if s == "" {
+		return nil, errInvalidVersion
+	}
+	parts := strings.Split(s, ".")
+	v := make(Version, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nil, errInvalidVersion
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+func (v Version) String() string {
+	parts := make([]string, len(v))
+	for i, n := range v {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ".")
+}
+
+// Compare returns -1 if v is lower than w, 0 if they are equal and +1 if v
+// is higher.  Missing components are zeros, so 1.2 equals 1.2.0.
+func (v Version) Compare(w Version) int {
+	for i := 0; i < len(v) || i < len(w); i++ {
+		var a, b int
+		if i < len(v) {
+			a = v[i]
+		}
+		if i < len(w) {
+			b = w[i]
+		}
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	}
+	return 0
+}
+
+// supportedFormat is the major nsfile format version the package supports.
+const supportedFormat = 1
+
+// CheckFormat returns an error if the nsfile format version is not
+// supported by the package.
+func (h Header) CheckFormat() error {
+	if len(h.Format) == 0 || h.Format[0] != supportedFormat {
+		return errUnsupportedFormat
+	}
+	return nil
+}
+
+// Header returns the header of the file.  If the version lines can't be
+// parsed, the error is returned along with the header, that has the rest of
+// the fields set.
+func (ns *NSFile) Header() (Header, error) {
+	hdr := ns.header
+	if hdr == nil {
+		return Header{}, errNotInitialised
+	}
+	h := Header{
+		ByteOrder: hdr.byteOrder,
+		Unicode:   hdr.format.unicode,
+		DOS:       hdr.format.dos,
+	}
+	siebel, serr := parseSiebelVersion(hdr.version.siebel)
+	format, ferr := ParseVersion(strings.TrimSpace(hdr.version.nsfile))
+	h.Siebel, h.Format = siebel, format
+	if serr != nil {
+		return h, serr
+	}
+	return h, ferr
+}
+
+// parseSiebelVersion parses the version line "16.0.0.0 [23057] ENU", the
+// language is optional.
+func parseSiebelVersion(line string) (SiebelVersion, error) {
+	v := SiebelVersion{Raw: line}
+	fields := strings.Fields(line)
+	if len(fields) < 2 || len(fields) > 3 {
+		return v, errInvalidVersion
+	}
+	var err error
+	if v.Version, err = ParseVersion(fields[0]); err != nil {
+		return v, err
+	}
+	build := fields[1]
+	if !strings.HasPrefix(build, "[") || !strings.HasSuffix(build, "]") {
+		return v, errInvalidVersion
+	}
+	if v.Build, err = strconv.Atoi(build[1 : len(build)-1]); err != nil {
+		return v, errInvalidVersion
+	}
+	if len(fields) == 3 {
+		v.Language = fields[2]
+	}
+	return v, nil
+}
@@ -0,0 +1,138 @@
+package siebns
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNSFile_Header(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     Header
+		wantErr  error
+	}{
+		{"sample", testfileSample, Header{
+			Siebel:    SiebelVersion{Version: Version{16, 0, 0, 0}, Build: 23057, Language: "ENU", Raw: "16.0.0.0 [23057] ENU"},
+			Format:    Version{1, 2},
+			ByteOrder: binary.LittleEndian,
+		}, nil},
+		{"dos unicode", "\xef\xbb\xbf" + strings.Replace(strings.Replace(testfileSample, "DAMAAAAAAAA=", "AAAAAAAAAww=", 1), "\n", "\r\n", -1), Header{
+			Siebel:    SiebelVersion{Version: Version{16, 0, 0, 0}, Build: 23057, Language: "ENU", Raw: "16.0.0.0 [23057] ENU"},
+			Format:    Version{1, 2},
+			ByteOrder: binary.BigEndian,
+			Unicode:   true,
+			DOS:       true,
+		}, nil},
+		{"bad version", strings.Replace(testfileSample, "16.0.0.0 [23057] ENU", "16.0 build 1", 1), Header{
+			Siebel:    SiebelVersion{Version: Version{16, 0}, Raw: "16.0 build 1"},
+			Format:    Version{1, 2},
+			ByteOrder: binary.LittleEndian,
+		}, errInvalidVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns, err := ParseBytes([]byte(tt.contents))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ns.Header()
+			if err != tt.wantErr {
+				t.Errorf("Header() error = %v, want %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Header() mismatch, (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_parseSiebelVersion(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    SiebelVersion
+		wantErr bool
+	}{
+		{"8.1.1.11 [23030] ENU", SiebelVersion{Version: Version{8, 1, 1, 11}, Build: 23030, Language: "ENU"}, false},
+		{"8.0 [20405]", SiebelVersion{Version: Version{8, 0}, Build: 20405}, false},
+		{"8.0 [x] ENU", SiebelVersion{Version: Version{8, 0}}, true},
+		{"8.0 23030 ENU", SiebelVersion{Version: Version{8, 0}}, true},
+		{"8.a [1] ENU", SiebelVersion{}, true},
+		{"8.0", SiebelVersion{}, true},
+		{"8.0 [1] ENU extra", SiebelVersion{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := parseSiebelVersion(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseSiebelVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			tt.want.Raw = tt.line
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("parseSiebelVersion() mismatch, (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestVersion_Compare(t *testing.T) {
+	tests := []struct {
+		v, w string
+		want int
+	}{
+		{"1.2", "1.2", 0},
+		{"1.2", "1.2.0", 0},
+		{"1.2", "1.10", -1},
+		{"16.0.0.0", "8.1.1.11", 1},
+		{"8.1", "8.1.1", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.v+" "+tt.w, func(t *testing.T) {
+			v, err := ParseVersion(tt.v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w, err := ParseVersion(tt.w)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := v.Compare(w); got != tt.want {
+				t.Errorf("Compare() = %d, want %d", got, tt.want)
+			}
+			if v.String() != tt.v {
+				t.Errorf("String() = %q, want %q", v.String(), tt.v)
+			}
+		})
+	}
+}
+
+func TestParseVersion_errors(t *testing.T) {
+	for _, s := range []string{"", "1.", ".1", "1.a", "1.-2", "1 .2"} {
+		if _, err := ParseVersion(s); err != errInvalidVersion {
+			t.Errorf("ParseVersion(%q) error = %v, want %v", s, err, errInvalidVersion)
+		}
+	}
+}
+
+func TestHeader_CheckFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Version
+		want   error
+	}{
+		{"1.2", Version{1, 2}, nil},
+		{"1.1", Version{1, 1}, nil},
+		{"2.0", Version{2, 0}, errUnsupportedFormat},
+		{"unknown", nil, errUnsupportedFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (Header{Format: tt.format}).CheckFormat(); err != tt.want {
+				t.Errorf("CheckFormat() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
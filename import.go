@@ -0,0 +1,380 @@
+package siebns
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// fieldError is an error in the field of the imported file.
+type fieldError struct {
+	field string
+	err   error
+}
+
+func (e *fieldError) Error() string {
+	return e.field + ": " + e.err.Error()
+}
+
+// ImportJSON reads the file in the export format (see "Export format")
+// from r, as written by ExportJSON.
+//
+// The header defines the versions, the byte order of the encoded size, the
+// BOM and line endings of the file.  Each node becomes the section, in the
+// order of the input, so that the parent always precedes its children,
+// except for the node with the "section" field set to false, that has no
+// section.
+// String values are quoted, integer and boolean values are checked against
+// the Type of the section.  Input that would produce the invalid file, such
+// as the Value without Type, or a line break in the value, is refused.
+//
+// The returned NSFile is read-only, use WriteTo to write it.
+func ImportJSON(r io.Reader) (*NSFile, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	v, err := decodeJSON(dec)
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	return importFile(v)
+}
+
+// ImportYAML reads the file in the export format (see "Export format")
+// from r, as written by ExportYAML.  See ImportJSON for details.
+func ImportYAML(r io.Reader) (*NSFile, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var v yaml.MapSlice
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return importFile(v)
+}
+
+// importFile builds the file from the decoded export format.
+func importFile(v interface{}) (*NSFile, error) {
+	top, ok := asObject(v)
+	if !ok {
+		return nil, errFieldType
+	}
+	var (
+		hdr  *nsHeader
+		root object
+		err  error
+	)
+	for _, item := range top {
+		switch key := fieldName(item.Key); key {
+		case fieldHeader:
+			hdr, err = importHeader(item.Value)
+			if err != nil {
+				return nil, err
+			}
+		case fieldRoot:
+			if root, ok = asObject(item.Value); !ok {
+				return nil, &fieldError{key, errFieldType}
+			}
+		default:
+			return nil, &fieldError{key, errUnexpectedField}
+		}
+	}
+	if hdr == nil {
+		return nil, &fieldError{fieldHeader, errMissingField}
+	}
+	if root == nil {
+		return nil, &fieldError{fieldRoot, errMissingField}
+	}
+
+	// Siebel separates the header from the sections with the empty line.
+	ns := &NSFile{header: hdr, body: body{preamble: []string{hdr.eol()}}}
+	if err := importNode(&ns.body, "/", root); err != nil {
+		return nil, err
+	}
+	data, err := ns.render()
+	if err != nil {
+		return nil, err
+	}
+	return ParseBytes(data)
+}
+
+func importHeader(v interface{}) (*nsHeader, error) {
+	obj, ok := asObject(v)
+	if !ok {
+		return nil, &fieldError{fieldHeader, errFieldType}
+	}
+	var hdr nsHeader
+	for _, item := range obj {
+		key := fieldName(item.Key)
+		var err error
+		switch key {
+		case fieldSiebel:
+			hdr.version.siebel, err = importString(item.Value)
+		case fieldNSFile:
+			hdr.version.nsfile, err = importString(item.Value)
+		case fieldByteOrder:
+			hdr.byteOrder, err = importByteOrder(item.Value)
+		case fieldUnicode:
+			hdr.format.unicode, ok = item.Value.(bool)
+		case fieldDOS:
+			hdr.format.dos, ok = item.Value.(bool)
+		default:
+			err = errUnexpectedField
+		}
+		if err == nil && !ok {
+			err = errFieldType
+		}
+		if err != nil {
+			return nil, &fieldError{fieldHeader + "." + key, err}
+		}
+	}
+	switch {
+	case hdr.version.siebel == "":
+		return nil, &fieldError{fieldHeader + "." + fieldSiebel, errMissingField}
+	case hdr.version.nsfile == "":
+		return nil, &fieldError{fieldHeader + "." + fieldNSFile, errMissingField}
+	case hdr.byteOrder == nil:
+		return nil, &fieldError{fieldHeader + "." + fieldByteOrder, errMissingField}
+	}
+	return &hdr, nil
+}
+
+func importByteOrder(v interface{}) (binary.ByteOrder, error) {
+	s, err := importString(v)
+	if err != nil {
+		return nil, err
+	}
+	switch s {
+	case "little":
+		return binary.LittleEndian, nil
+	case "big":
+		return binary.BigEndian, nil
+	}
+	return nil, errUnknownByteOrder
+}
+
+// importNode appends the section p, built from the node obj, and sections
+// of its descendants to b.
+func importNode(b *body, p string, obj object) error {
+	var (
+		children object
+		typ      string
+		section  = true
+		attrs    = make([]yaml.MapItem, 0, len(obj))
+		seen     = make(map[string]bool, len(obj))
+	)
+	for _, item := range obj {
+		key := fieldName(item.Key)
+		if seen[key] {
+			return &fieldError{"[" + p + "] " + key, errDuplicateName}
+		}
+		seen[key] = true
+		if key == keyChildren {
+			var ok bool
+			if children, ok = asObject(item.Value); !ok {
+				return &fieldError{"[" + p + "] " + key, errFieldType}
+			}
+			continue
+		}
+		if key == keySection {
+			var ok bool
+			if section, ok = item.Value.(bool); !ok {
+				return &fieldError{"[" + p + "] " + key, errFieldType}
+			}
+			continue
+		}
+		if !validKey(key) {
+			return &fieldError{"[" + p + "] " + key, errInvalidName}
+		}
+		if key == keyType {
+			typ, _ = item.Value.(string)
+		}
+		attrs = append(attrs, yaml.MapItem{Key: key, Value: item.Value})
+	}
+	if !section {
+		if len(attrs) > 0 {
+			return &fieldError{"[" + p + "] " + attrs[0].Key.(string), errUnexpectedField}
+		}
+		return importChildren(b, p, children)
+	}
+
+	s := &Section{Path: p}
+	b.sections = append(b.sections, s)
+	for _, item := range attrs {
+		key := item.Key.(string)
+		value, err := importValue(typ, key, item.Value)
+		if err != nil {
+			return &fieldError{"[" + p + "] " + key, err}
+		}
+		s.Attrs = append(s.Attrs, &Attr{Key: key, Value: value})
+	}
+	switch {
+	case seen[keyValue] && !seen[keyType]:
+		return &sectionError{p, errNoType}
+	case !seen[keyValue] && (typ == TypeString || typ == TypeInteger || typ == TypeBoolean):
+		return &sectionError{p, errNoValue}
+	}
+	return importChildren(b, p, children)
+}
+
+// importChildren appends the sections of the child nodes of the node p to
+// b.
+func importChildren(b *body, p string, children object) error {
+	for _, item := range children {
+		name := fieldName(item.Key)
+		cp := joinPath(p, name)
+		if name == "" || strings.Contains(name, "/") || checkPath(cp) != nil || strings.ContainsAny(name, "\r\n") {
+			return &fieldError{"[" + cp + "]", errInvalidName}
+		}
+		obj, ok := asObject(item.Value)
+		if !ok {
+			return &fieldError{"[" + cp + "]", errFieldType}
+		}
+		if b.index(cp) >= 0 {
+			return &sectionError{cp, errSectionExists}
+		}
+		if err := importNode(b, cp, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importValue returns the attribute value as it is written to the file.
+// The Value is checked against the Type of the section typ and quoted, if
+// it's the string.
+func importValue(typ, key string, v interface{}) (string, error) {
+	if key != keyValue {
+		return importString(v)
+	}
+	switch typ {
+	case TypeInteger:
+		var s string
+		switch n := v.(type) {
+		case json.Number:
+			s = n.String()
+		case int:
+			s = strconv.Itoa(n)
+		case int64:
+			s = strconv.FormatInt(n, 10)
+		case uint64:
+			s = strconv.FormatUint(n, 10)
+		case string:
+			s = n
+		default:
+			return "", errTypeMismatch
+		}
+		if _, err := strconv.ParseInt(s, 10, 64); err != nil {
+			return "", errInvalidValue
+		}
+		return s, nil
+	case TypeBoolean:
+		switch b := v.(type) {
+		case bool:
+			return formatBool(b), nil
+		case string:
+			if _, ok := parseBool(b); ok {
+				return b, nil
+			}
+			return "", errInvalidValue
+		}
+		return "", errTypeMismatch
+	case TypeString:
+		s, err := importString(v)
+		if err != nil {
+			return "", err
+		}
+		return quote(s), nil
+	}
+	return importString(v)
+}
+
+// importString returns v if it is the single line string.
+func importString(v interface{}) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", errFieldType
+	}
+	if strings.ContainsAny(s, "\r\n") {
+		return "", errLineBreak
+	}
+	return s, nil
+}
+
+// validKey returns true if key can be written as the attribute name.
+func validKey(key string) bool {
+	return key != "" &&
+		!strings.ContainsAny(key, "=\r\n") &&
+		strings.TrimLeft(key, " \t") == key
+}
+
+// asObject returns v as the object, if it is one.
+func asObject(v interface{}) (object, bool) {
+	switch o := v.(type) {
+	case object:
+		return o, true
+	case yaml.MapSlice:
+		return object(o), true
+	}
+	return nil, false
+}
+
+// fieldName returns the name of the field.  YAML allows keys of any type,
+// i.e. the server named 1234 has the integer key.
+func fieldName(key interface{}) string {
+	if s, ok := key.(string); ok {
+		return s
+	}
+	return fmt.Sprint(key)
+}
+
+// decodeJSON decodes the next JSON value from dec, keeping the order of
+// object fields.
+func decodeJSON(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+	switch delim {
+	case '{':
+		obj := object{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, yaml.MapItem{Key: key, Value: v})
+		}
+		_, err = dec.Token() // closing brace
+		return obj, err
+	case '[':
+		var arr []interface{}
+		for dec.More() {
+			v, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		_, err = dec.Token() // closing bracket
+		return arr, err
+	}
+	return nil, fmt.Errorf("unexpected %s", delim)
+}
@@ -0,0 +1,141 @@
+package siebns
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestImport_roundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+	}{
+		{"unix", testfileSample},
+		{"windows paths", testfileSample + "[/enterprises/SBA_84/parameters/FSPath]\n\tType=string\n\tValue=\"\\\\srv\\fs\"\n[/enterprises/SBA_84/parameters/Root]\n\tType=string\n\tValue=\"C:\\siebel\\\"\n"},
+		{"section-less node", testfileSample + "[/enterprises/SBA_84/profiles/GatewayDataSrc]\n\tType=empty\n"},
+		{"dos big endian", "\xef\xbb\xbf" + strings.Replace(strings.Replace(testfileSample, "DAMAAAAAAAA=", "AAAAAAAAAww=", 1), "\n", "\r\n", -1)},
+	}
+	formats := []struct {
+		name   string
+		export func(ns *NSFile, buf *bytes.Buffer) error
+		imp    func(buf *bytes.Buffer) (*NSFile, error)
+	}{
+		{"json",
+			func(ns *NSFile, buf *bytes.Buffer) error { return ns.ExportJSON(buf) },
+			func(buf *bytes.Buffer) (*NSFile, error) { return ImportJSON(buf) }},
+		{"yaml",
+			func(ns *NSFile, buf *bytes.Buffer) error { return ns.ExportYAML(buf) },
+			func(buf *bytes.Buffer) (*NSFile, error) { return ImportYAML(buf) }},
+	}
+	for _, tt := range tests {
+		for _, f := range formats {
+			t.Run(tt.name+" "+f.name, func(t *testing.T) {
+				want := withValidSize(tt.contents)
+				ns, err := ParseBytes([]byte(want))
+				if err != nil {
+					t.Fatal(err)
+				}
+				var buf bytes.Buffer
+				if err := f.export(ns, &buf); err != nil {
+					t.Fatal(err)
+				}
+				imported, err := f.imp(&buf)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var out bytes.Buffer
+				if _, err := imported.WriteTo(&out); err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(want, out.String()); diff != "" {
+					t.Errorf("imported file mismatch, (-want,+got):\n%s", diff)
+				}
+			})
+		}
+	}
+}
+
+func TestImportYAML(t *testing.T) {
+	const header = "header: {siebel: \"16.0.0.0 [23057] ENU\", nsfile: \"1.2\", byteOrder: little}\n"
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr string
+	}{
+		{"values",
+			header + "root:\n  Type: empty\n  children:\n    1234:\n      Type: boolean\n      Value: Y\n    s:\n      Type: string\n      Value: a \"b\"\n    i:\n      Type: integer\n      Value: \"42\"\n",
+			"[/]\n\tType=empty\n[/1234]\n\tType=boolean\n\tValue=True\n[/s]\n\tType=string\n\tValue=\"a \\\"b\\\"\"\n[/i]\n\tType=integer\n\tValue=42\n", ""},
+		{"implicit node",
+			header + "root:\n  children:\n    a:\n      children:\n        b: {}\n",
+			"[/]\n[/a]\n[/a/b]\n", ""},
+		{"section-less node",
+			header + "root:\n  children:\n    a:\n      section: false\n      children:\n        b: {}\n",
+			"[/]\n[/a/b]\n", ""},
+		{"no header", "root: {}\n", "", "header: missing field"},
+		{"no root", header, "", "root: missing field"},
+		{"unexpected field", header + "root: {}\nextra: 1\n", "", "extra: unexpected field"},
+		{"no version", "header: {nsfile: \"1.2\", byteOrder: little}\nroot: {}\n", "", "header.siebel: missing field"},
+		{"nsfile number", "header: {siebel: x, nsfile: 1.2, byteOrder: little}\nroot: {}\n", "", "header.nsfile: " + errFieldType.Error()},
+		{"byte order", "header: {siebel: x, nsfile: \"1.2\", byteOrder: middle}\nroot: {}\n", "", "header.byteOrder: " + errUnknownByteOrder.Error()},
+		{"value without type", header + "root: {Value: \"1\"}\n", "", "/: " + errNoType.Error()},
+		{"no value", header + "root: {Type: integer}\n", "", "/: " + errNoValue.Error()},
+		{"not integer", header + "root: {Type: integer, Value: 1.5}\n", "", "[/] Value: " + errTypeMismatch.Error()},
+		{"invalid integer", header + "root: {Type: integer, Value: abc}\n", "", "[/] Value: " + errInvalidValue.Error()},
+		{"invalid boolean", header + "root: {Type: boolean, Value: maybe}\n", "", "[/] Value: " + errInvalidValue.Error()},
+		{"line break", header + "root: {Description: \"a\\nb\"}\n", "", "[/] Description: " + errLineBreak.Error()},
+		{"invalid key", header + "root: {\"a=b\": c}\n", "", "[/] a=b: " + errInvalidName.Error()},
+		{"invalid name", header + "root: {children: {\"..\": {}}}\n", "", "[/..]: " + errInvalidName.Error()},
+		{"slash in name", header + "root: {children: {\"a/b\": {}}}\n", "", "[/a/b]: " + errInvalidName.Error()},
+		{"node not object", header + "root: {children: {a: 1}}\n", "", "[/a]: " + errFieldType.Error()},
+		{"section not boolean", header + "root: {section: \"no\"}\n", "", "[/] section: " + errFieldType.Error()},
+		{"section-less attribute", header + "root: {section: false, Type: empty}\n", "", "[/] Type: " + errUnexpectedField.Error()},
+		{"non-string attribute", header + "root: {Persistence: 1}\n", "", "[/] Persistence: " + errFieldType.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns, err := ImportYAML(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("ImportYAML() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			if _, err := ns.WriteTo(&out); err != nil {
+				t.Fatal(err)
+			}
+			want := withValidSize("Siebel Name Server Backing File\n16.0.0.0 [23057] ENU\n1.2\nDAMAAAAAAAA=             \n\n" + tt.want)
+			if diff := cmp.Diff(want, out.String()); diff != "" {
+				t.Errorf("imported file mismatch, (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestImportJSON_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"duplicate", `{"header": {"siebel": "x", "nsfile": "1.2", "byteOrder": "big"}, "root": {"Type": "empty", "Type": "empty"}}`, "[/] Type: " + errDuplicateName.Error()},
+		{"array", `{"header": {"siebel": "x", "nsfile": "1.2", "byteOrder": "big"}, "root": {"children": []}}`, "[/] children: " + errFieldType.Error()},
+		{"not object", `[]`, errFieldType.Error()},
+		{"syntax", `{"header": `, "unexpected EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ImportJSON(strings.NewReader(tt.input))
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("ImportJSON() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
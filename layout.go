@@ -0,0 +1,139 @@
+package siebns
+
+import "strconv"
+
+// Layout of the gateway configuration
+//
+// The gateway keeps the configuration of enterprises in the sections under
+// /enterprises.  Parameters are the sections named after the parameter
+// alias, under the "parameters" section of the object they are set for:
+//
+//	/enterprises/<enterprise>/parameters/<alias>
+//	/enterprises/<enterprise>/servers/<server>/parameters/<alias>
+//	/enterprises/<enterprise>/servers/<server>/components/<component>/parameters/<alias>
+//	/enterprises/<enterprise>/components/<component>/parameters/<alias>
+//	/enterprises/<enterprise>/component groups/<group>/parameters/<alias>
+//	/enterprises/<enterprise>/named subsystems/<subsystem>/parameters/<alias>
+//	/enterprises/<enterprise>/component definitions/<component>/parameters/<alias>
+//
+// Component groups assigned to the server are the sections
+// /enterprises/<enterprise>/servers/<server>/component groups/<group>.
+
+// Names of the sections that hold the objects of the enterprise.
+const (
+	dirEnterprises     = "enterprises"
+	dirServers         = "servers"
+	dirComponents      = "components"
+	dirComponentGroups = "component groups"
+	dirNamedSubsystems = "named subsystems"
+	dirDefinitions     = "component definitions"
+	dirParameters      = "parameters"
+)
+
+// Level is the level of the configuration the parameter is set at.
+type Level int
+
+// Levels of the configuration.  The levels from LevelDefinition to
+// LevelServerComponent make the inheritance chain: the parameter set at the
+// level overrides the one set at the levels before it, i.e. the server
+// component parameter overrides the server parameter.  Component groups and
+// named subsystems are not the part of the chain, their parameters don't
+// override and are not overridden by the parameters of other levels.
+const (
+	LevelDefinition      Level = iota // component definition
+	LevelEnterprise                   // enterprise
+	LevelServer                       // server
+	LevelComponent                    // component of the enterprise
+	LevelServerComponent              // component of the server
+	LevelComponentGroup               // component group
+	LevelNamedSubsystem               // named subsystem
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDefinition:
+		return "component definition"
+	case LevelEnterprise:
+		return "enterprise"
+	case LevelServer:
+		return "server"
+	case LevelComponent:
+		return "component"
+	case LevelServerComponent:
+		return "server component"
+	case LevelComponentGroup:
+		return "component group"
+	case LevelNamedSubsystem:
+		return "named subsystem"
+	default:
+		return "Level(" + strconv.Itoa(int(l)) + ")"
+	}
+}
+
+// objectDirs are the sections of the enterprise holding the objects that
+// may have parameters, by the level of the object.
+var objectDirs = map[string]Level{
+	dirComponents:      LevelComponent,
+	dirComponentGroups: LevelComponentGroup,
+	dirNamedSubsystems: LevelNamedSubsystem,
+	dirDefinitions:     LevelDefinition,
+}
+
+// paramLocation is the location of the parameter in the configuration.
+type paramLocation struct {
+	enterprise string
+	server     string
+	level      Level
+	object     string // component, component group or named subsystem
+	alias      string
+}
+
+// parseParamPath returns the location of the parameter section p, or false
+// if p is not the parameter section.
+func parseParamPath(p string) (paramLocation, bool) {
+	elems := splitPath(p)
+	n := len(elems)
+	if n < 4 || elems[0] != dirEnterprises || elems[n-2] != dirParameters {
+		return paramLocation{}, false
+	}
+	loc := paramLocation{enterprise: elems[1], alias: elems[n-1]}
+	obj := elems[2 : n-2]
+	switch {
+	case len(obj) == 0:
+		loc.level = LevelEnterprise
+	case len(obj) == 2 && obj[0] == dirServers:
+		loc.level, loc.server = LevelServer, obj[1]
+	case len(obj) == 4 && obj[0] == dirServers && obj[2] == dirComponents:
+		loc.level, loc.server, loc.object = LevelServerComponent, obj[1], obj[3]
+	case len(obj) == 2:
+		level, ok := objectDirs[obj[0]]
+		if !ok {
+			return paramLocation{}, false
+		}
+		loc.level, loc.object = level, obj[1]
+	default:
+		return paramLocation{}, false
+	}
+	return loc, true
+}
+
+// paramPath returns the path of the parameter section at the location.
+func paramPath(loc paramLocation) string {
+	p := joinPath("/"+dirEnterprises, loc.enterprise)
+	switch loc.level {
+	case LevelServer:
+		p = joinPath(joinPath(p, dirServers), loc.server)
+	case LevelServerComponent:
+		p = joinPath(joinPath(p, dirServers), loc.server)
+		p = joinPath(joinPath(p, dirComponents), loc.object)
+	case LevelComponent:
+		p = joinPath(joinPath(p, dirComponents), loc.object)
+	case LevelComponentGroup:
+		p = joinPath(joinPath(p, dirComponentGroups), loc.object)
+	case LevelNamedSubsystem:
+		p = joinPath(joinPath(p, dirNamedSubsystems), loc.object)
+	case LevelDefinition:
+		p = joinPath(joinPath(p, dirDefinitions), loc.object)
+	}
+	return joinPath(joinPath(p, dirParameters), loc.alias)
+}
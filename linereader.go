@@ -10,6 +10,7 @@ type lineReader interface {
 	readline() []byte
 	readstring() string
 	position() int64
+	lineno() int
 	err() error
 }
 
@@ -20,19 +21,26 @@ func newLineReader(r io.Reader) lineReader {
 type lnReader struct {
 	r       *bufio.Reader
 	pos     int64
+	line    int
 	lastErr error
 }
 
+// readline returns the next line including the line ending.  The last line
+// of the file that is not terminated with the line ending is returned as
+// is, and err() reports io.EOF afterwards.
 func (lr *lnReader) readline() (line []byte) {
 	if lr.lastErr != nil {
 		return
 	}
 	line, err := lr.r.ReadBytes('\n')
+	if len(line) > 0 {
+		lr.pos += int64(len(line))
+		lr.line++
+	}
 	if err != nil {
 		lr.lastErr = err
 		return
 	}
-	lr.pos += int64(len(line))
 
 	return
 }
@@ -45,6 +53,11 @@ func (lr *lnReader) position() int64 {
 	return lr.pos
 }
 
+// lineno returns the number of the last line read, starting from 1.
+func (lr *lnReader) lineno() int {
+	return lr.line
+}
+
 func (lr *lnReader) err() error {
 	return lr.lastErr
 }
@@ -0,0 +1,84 @@
+package siebns
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// lockPollInterval is how often the lock is retried while waiting for it.
+const lockPollInterval = 50 * time.Millisecond
+
+// lockError is returned when the file is locked by another process.
+type lockError struct {
+	path string
+	pid  int // pid of the lock holder, 0 if unknown
+}
+
+func (e *lockError) Error() string {
+	if e.pid == 0 {
+		return fmt.Sprintf("%s: %s", e.path, errLocked)
+	}
+	return fmt.Sprintf("%s: %s by process %d", e.path, errLocked, e.pid)
+}
+
+// WithLock makes Open acquire the exclusive advisory lock on the file,
+// waiting for the lock up to timeout if it's held by another process.  The
+// lock is held until the file is closed, including the file that replaces
+// the original on Save.  Only the processes that use the locking are
+// affected, the editors and the Siebel Gateway normally don't.  On systems
+// that don't support locking, the file is opened without the lock.
+func WithLock(timeout time.Duration) Option {
+	return func(ns *NSFile) {
+		ns.lock = true
+		ns.lockTimeout = timeout
+	}
+}
+
+// lockFile acquires the exclusive lock on f, the file opened at path,
+// waiting for it up to timeout.  While waiting, the lock holder may replace
+// the file with Save, that renames the new file over path, and then the
+// lock is acquired on the replaced file, that is no longer at path.  In
+// that case f is closed, and the file at path is reopened with open and
+// locked again.  The locked file is returned, on error it is the file that
+// the caller should close.
+func lockFile(f *os.File, path string, open func() (*os.File, error), timeout time.Duration) (*os.File, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		if err := lockUntil(f, deadline); err != nil {
+			return f, err
+		}
+		fi, err := f.Stat()
+		if err != nil {
+			return f, err
+		}
+		pfi, err := os.Stat(path)
+		if err != nil {
+			return f, err
+		}
+		if os.SameFile(fi, pfi) {
+			return f, nil
+		}
+		nf, err := open()
+		if err != nil {
+			return f, err
+		}
+		f.Close()
+		f = nf
+	}
+}
+
+// lockUntil acquires the exclusive lock on f, waiting for it until the
+// deadline.
+func lockUntil(f *os.File, deadline time.Time) error {
+	for {
+		err := tryLock(f)
+		if err != errLocked {
+			return err
+		}
+		if !time.Now().Before(deadline) {
+			return &lockError{path: f.Name(), pid: lockHolder(f)}
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
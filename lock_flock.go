@@ -0,0 +1,78 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package siebns
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// tryLock attempts to acquire the exclusive lock on f without blocking, it
+// returns errLocked if the lock is held by another process.
+func tryLock(f *os.File) error {
+	err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err == syscall.EWOULDBLOCK {
+		return errLocked
+	}
+	return err
+}
+
+// lockHolder returns the pid of the process holding the lock on f, or 0 if
+// it can't be determined.  It relies on /proc/locks, which is only
+// available on Linux.
+func lockHolder(f *os.File) int {
+	fi, err := f.Stat()
+	if err != nil {
+		return 0
+	}
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0
+	}
+	locks, err := os.Open("/proc/locks")
+	if err != nil {
+		return 0
+	}
+	defer locks.Close()
+
+	// 1: FLOCK  ADVISORY  WRITE 1234 08:01:5678 0 EOF
+	// 1: -> FLOCK  ADVISORY  WRITE 4321 08:01:5678 0 EOF (waiting)
+	sc := bufio.NewScanner(locks)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 6 || fields[1] != "FLOCK" {
+			continue
+		}
+		dev := strings.Split(fields[5], ":")
+		if len(dev) != 3 {
+			continue
+		}
+		major, err1 := strconv.ParseUint(dev[0], 16, 32)
+		minor, err2 := strconv.ParseUint(dev[1], 16, 32)
+		ino, err3 := strconv.ParseUint(dev[2], 10, 64)
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		if ino != uint64(st.Ino) || !sameDev(uint64(st.Dev), major, minor) {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[4])
+		if err != nil {
+			continue
+		}
+		return pid
+	}
+	return 0
+}
+
+// sameDev returns true if the device number dev, as encoded by Linux, has
+// the major and minor numbers.
+func sameDev(dev uint64, major, minor uint64) bool {
+	devMajor := (dev>>8)&0xfff | (dev>>32)&^0xfff
+	devMinor := dev&0xff | (dev>>12)&^0xff
+	return devMajor == major && devMinor == minor
+}
@@ -0,0 +1,16 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly
+
+package siebns
+
+import "os"
+
+// tryLock is not supported on this system.
+func tryLock(f *os.File) error {
+	return errLockUnsupported
+}
+
+// lockHolder is not supported on this system.
+func lockHolder(f *os.File) int {
+	return 0
+}
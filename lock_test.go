@@ -0,0 +1,122 @@
+package siebns
+
+import (
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestWithLock(t *testing.T) {
+	dir, name := createTestDir(t, withValidSize(testfileSample))
+	defer os.RemoveAll(dir)
+
+	ns, err := Open(name, WithLock(0), WithBackups(NoBackup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ns.lock {
+		ns.Close()
+		t.Skip(errLockUnsupported)
+	}
+
+	start := time.Now()
+	_, err = Open(name, WithLock(2*lockPollInterval))
+	le, ok := err.(*lockError)
+	if !ok {
+		t.Fatalf("Open() error = %v, want lockError", err)
+	}
+	if time.Since(start) < 2*lockPollInterval {
+		t.Errorf("Open() did not wait for the lock")
+	}
+	if runtime.GOOS == "linux" && le.pid != os.Getpid() {
+		t.Errorf("lock holder pid = %d, want %d", le.pid, os.Getpid())
+	}
+
+	// the lock must survive the save, that replaces the file.
+	if err := ns.SetParam("/enterprises/SBA_84/parameters/MaxTasks", "Value", "200"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(name, WithLock(0)); err == nil {
+		t.Error("file is not locked after Save")
+	}
+
+	ns.Close()
+	locked, err := Open(name, WithLock(0))
+	if err != nil {
+		t.Fatalf("Open() after Close() error = %v", err)
+	}
+	locked.Close()
+}
+
+func TestWithLock_contention(t *testing.T) {
+	dir, name := createTestDir(t, withValidSize(testfileSample))
+	defer os.RemoveAll(dir)
+
+	opts := []Option{WithLock(5 * time.Second), WithBackups(NoBackup), WithWriteCheck(nil)}
+	a, err := Open(name, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.lock {
+		a.Close()
+		t.Skip(errLockUnsupported)
+	}
+
+	// b waits for the lock, while a replaces the file with Save.
+	type result struct {
+		ns  *NSFile
+		err error
+	}
+	opened := make(chan result)
+	go func() {
+		b, err := Open(name, opts...)
+		opened <- result{b, err}
+	}()
+	time.Sleep(2 * lockPollInterval)
+
+	const p = "/enterprises/SBA_84/parameters/MaxTasks"
+	if err := a.SetParam(p, "A", "A"); err != nil {
+		t.Fatal(err)
+	}
+	a.Close()
+
+	res := <-opened
+	if res.err != nil {
+		t.Fatal(res.err)
+	}
+	b := res.ns
+	defer b.Close()
+	if err := b.SetParam(p, "B", "B"); err != nil {
+		t.Fatal(err)
+	}
+
+	reread, err := OpenReadOnly(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reread.Close()
+	for _, key := range []string{"A", "B"} {
+		if _, ok := reread.Section(p).Get(key); !ok {
+			t.Errorf("edit %s is lost", key)
+		}
+	}
+}
+
+func Test_lockError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *lockError
+		want string
+	}{
+		{"pid", &lockError{path: "siebns.dat", pid: 1234}, "siebns.dat: file is locked by process 1234"},
+		{"no pid", &lockError{path: "siebns.dat"}, "siebns.dat: file is locked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("lockError.Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
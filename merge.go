@@ -0,0 +1,182 @@
+package siebns
+
+import (
+	"fmt"
+	"path"
+	"sort"
+)
+
+// Conflict is the change made in theirs that was not merged, because ours
+// changed the same attribute or section differently.
+type Conflict struct {
+	Path   string `json:"path"`
+	Key    string `json:"key,omitempty"`
+	Ours   Change `json:"ours"`
+	Theirs Change `json:"theirs"`
+}
+
+func (c Conflict) String() string {
+	name := "[" + c.Path + "]"
+	if c.Key != "" {
+		name += " " + c.Key
+	}
+	return fmt.Sprintf("%s: ours %s; theirs %s", name, c.Ours, c.Theirs)
+}
+
+// Merge merges the changes made in ours and theirs since base, their common
+// ancestor.  The result is the copy of ours, so it keeps the formatting of
+// ours, with the changes of theirs applied.  The change of theirs that
+// conflicts with the change of ours is not applied, ours wins, and it is
+// reported as the Conflict.  Identical changes made in both files do not
+// conflict.
+//
+// The merged file is read-only, use WriteTo to write it, the encoded size
+// is computed when it is written.
+func Merge(base, ours, theirs *NSFile) (*NSFile, []Conflict, error) {
+	data, err := ours.render()
+	if err != nil {
+		return nil, nil, err
+	}
+	merged, err := ParseBytes(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	m := newMerger(&merged.body, Diff(base, ours))
+	if err := m.apply(Diff(base, theirs)); err != nil {
+		return nil, nil, err
+	}
+	sort.SliceStable(m.conflicts, func(i, j int) bool {
+		return m.conflicts[i].Path < m.conflicts[j].Path
+	})
+	return merged, m.conflicts, nil
+}
+
+// attrKey identifies the attribute of the section.
+type attrKey struct {
+	path string
+	key  string
+}
+
+// merger applies the changes of theirs to the copy of ours.
+type merger struct {
+	b         *body
+	ours      []Change
+	sections  map[string]Change  // section changes of ours
+	attrs     map[attrKey]Change // attribute changes of ours
+	conflicts []Conflict
+}
+
+func newMerger(b *body, ours []Change) *merger {
+	m := &merger{
+		b:        b,
+		ours:     ours,
+		sections: make(map[string]Change),
+		attrs:    make(map[attrKey]Change),
+	}
+	for _, c := range ours {
+		if c.Key == "" {
+			m.sections[c.Path] = c
+		} else {
+			m.attrs[attrKey{c.Path, c.Key}] = c
+		}
+	}
+	return m
+}
+
+func (m *merger) conflict(ours, theirs Change) {
+	m.conflicts = append(m.conflicts, Conflict{Path: theirs.Path, Key: theirs.Key, Ours: ours, Theirs: theirs})
+}
+
+// apply applies the changes of theirs.
+func (m *merger) apply(theirs []Change) error {
+	var removed []Change
+	for _, c := range theirs {
+		var err error
+		switch {
+		case c.Key == "" && c.Kind == Added:
+			err = m.addSection(c)
+		case c.Key == "" && c.Kind == Removed:
+			removed = append(removed, c)
+		default:
+			err = m.setAttr(c)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// changes are sorted by path, removing them in the reverse order
+	// removes descendants before their ancestors.
+	for i := len(removed) - 1; i >= 0; i-- {
+		if err := m.removeSection(removed[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *merger) addSection(c Change) error {
+	s, err := m.b.section(c.Path)
+	if err != nil {
+		if m.b.index(path.Dir(c.Path)) < 0 {
+			m.conflict(m.removedParent(c.Path), c)
+			return nil
+		}
+		return m.b.addSection(c.Path, c.Attrs)
+	}
+	// added in both, merge attributes as if the base section was empty.
+	for _, a := range c.Attrs {
+		ours := s.attr(a.Key)
+		switch {
+		case ours == nil:
+			s.Set(a.Key, a.Value)
+		case ours.Value != a.Value:
+			m.conflict(
+				Change{Kind: Added, Path: c.Path, Key: a.Key, New: ours.Value},
+				Change{Kind: Added, Path: c.Path, Key: a.Key, New: a.Value},
+			)
+		}
+	}
+	return nil
+}
+
+// removedParent returns the removal of the topmost ancestor of p that was
+// removed in ours.
+func (m *merger) removedParent(p string) Change {
+	removed := Change{Kind: Removed, Path: path.Dir(p)}
+	for dir := path.Dir(p); dir != "/"; dir = path.Dir(dir) {
+		if c, ok := m.sections[dir]; ok && c.Kind == Removed {
+			removed = c
+		}
+	}
+	return removed
+}
+
+func (m *merger) removeSection(c Change) error {
+	if oc, ok := m.sections[c.Path]; ok && oc.Kind == Removed {
+		return nil // removed in both
+	}
+	for _, oc := range m.ours {
+		if inSubtree(oc.Path, c.Path) && !(oc.Key == "" && oc.Kind == Removed) {
+			m.conflict(oc, c)
+			return nil
+		}
+	}
+	return m.b.deleteSection(c.Path)
+}
+
+func (m *merger) setAttr(c Change) error {
+	if oc, ok := m.sections[c.Path]; ok && oc.Kind == Removed {
+		m.conflict(oc, c)
+		return nil
+	}
+	if oc, ok := m.attrs[attrKey{c.Path, c.Key}]; ok {
+		if oc.Kind != c.Kind || oc.New != c.New {
+			m.conflict(oc, c)
+		}
+		return nil
+	}
+	if c.Kind == Removed {
+		return m.b.deleteParam(c.Path, c.Key)
+	}
+	return m.b.setParam(c.Path, c.Key, c.New)
+}
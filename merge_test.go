@@ -0,0 +1,105 @@
+package siebns
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMerge(t *testing.T) {
+	replace := func(s string, oldnew ...string) string {
+		return strings.NewReplacer(oldnew...).Replace(s)
+	}
+	parse := func(contents string) *NSFile {
+		ns, err := ParseBytes([]byte(contents))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ns
+	}
+	base := testfileSample + "[/enterprises/SBA_84/parameters/Timeout]\n\tType=integer\n\tValue=30\n"
+
+	ours := replace(base,
+		"\tValue=100\n", "\tValue=200\n",
+		"[/enterprises]\n\tPersistence=full\n", "[/enterprises]\n",
+		"\tValue=30\n", "\tValue=60\n",
+	) + "[/enterprises/SBA_84/parameters/MinTasks]\n\tType=integer\n\tValue=1\n"
+
+	theirs := replace(base,
+		"\tValue=100\n", "\tValue=300\n",
+		"[/enterprises]\n\tPersistence=full\n", "[/enterprises]\n",
+		"\"Siebel Enterprise\"", "\"Enterprise\"",
+		"[/]\n\tPersistence=partial\n", "[/]\n",
+		"[/enterprises/SBA_84/parameters/Timeout]\n\tType=integer\n\tValue=30\n", "",
+	) + "[/enterprises/SBA_84/parameters/MinTasks]\n\tType=integer\n\tValue=2\n\tPersistence=full\n" +
+		"[/enterprises/SBA_84/parameters/MaxProcs]\n\tType=integer\n\tValue=4\n"
+
+	merged, conflicts, err := Merge(parse(base), parse(ours), parse(theirs))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const p = "/enterprises/SBA_84/parameters/"
+	wantConflicts := []Conflict{
+		{Path: p + "MaxTasks", Key: "Value",
+			Ours:   Change{Kind: Changed, Path: p + "MaxTasks", Key: "Value", Old: "100", New: "200"},
+			Theirs: Change{Kind: Changed, Path: p + "MaxTasks", Key: "Value", Old: "100", New: "300"}},
+		{Path: p + "MinTasks", Key: "Value",
+			Ours:   Change{Kind: Added, Path: p + "MinTasks", Key: "Value", New: "1"},
+			Theirs: Change{Kind: Added, Path: p + "MinTasks", Key: "Value", New: "2"}},
+		{Path: p + "Timeout",
+			Ours:   Change{Kind: Changed, Path: p + "Timeout", Key: "Value", Old: "30", New: "60"},
+			Theirs: Change{Kind: Removed, Path: p + "Timeout", Attrs: []Attr{{Key: "Type", Value: "integer"}, {Key: "Value", Value: "30"}}}},
+	}
+	if diff := cmp.Diff(wantConflicts, conflicts, cmp.AllowUnexported(Attr{})); diff != "" {
+		t.Errorf("Merge() conflicts mismatch, (-want,+got):\n%s", diff)
+	}
+
+	var buf bytes.Buffer
+	if _, err := merged.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := withValidSize(replace(ours,
+		"[/]\n\tPersistence=partial\n", "[/]\n",
+		"\"Siebel Enterprise\"", "\"Enterprise\"",
+		"\tValue=1\n", "\tValue=1\n\tPersistence=full\n",
+	) + "[/enterprises/SBA_84/parameters/MaxProcs]\n\tType=integer\n\tValue=4\n")
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("merged file mismatch, (-want,+got):\n%s", diff)
+	}
+}
+
+func TestMerge_removedParent(t *testing.T) {
+	base, err := ParseBytes([]byte(testfileSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ours, err := ParseBytes([]byte(strings.SplitAfter(testfileSample, "\tType=empty\n")[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	theirs, err := ParseBytes([]byte(testfileSample + "[/enterprises/SBA_84/parameters/MaxTasks/x]\n\tType=empty\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, conflicts, err := Merge(base, ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 1 || conflicts[0].Ours.Kind != Removed || conflicts[0].Ours.Path != "/enterprises" {
+		t.Errorf("Merge() conflicts = %v", conflicts)
+	}
+}
+
+func TestConflict_String(t *testing.T) {
+	c := Conflict{Path: "/p", Key: "Value",
+		Ours:   Change{Kind: Changed, Path: "/p", Key: "Value", Old: "1", New: "2"},
+		Theirs: Change{Kind: Removed, Path: "/p", Key: "Value", Old: "1"},
+	}
+	want := "[/p] Value: ours ~ [/p] Value: 1 -> 2; theirs - [/p] Value=1"
+	if got := c.String(); got != want {
+		t.Errorf("Conflict.String() = %q, want %q", got, want)
+	}
+}
@@ -2,16 +2,20 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Package siebns currently only allows fixing the encoded file size
-// in Siebel Gateway Naming file after making manual modifications to it.
+// Package siebns allows reading the sections of the Siebel Gateway Naming
+// file and fixing the encoded file size after making manual modifications
+// to it.
 package siebns
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/binary"
 	"errors"
 	"io"
+	"io/ioutil"
 	"os"
+	"time"
 )
 
 // other constants
@@ -33,7 +37,7 @@ type version struct {
 	nsfile string
 }
 
-//nsHeader contains the information from NS file header
+// nsHeader contains the information from NS file header
 type nsHeader struct {
 	byteOrder binary.ByteOrder
 
@@ -50,11 +54,29 @@ type nsDisker interface {
 
 	Name() string
 	Stat() (os.FileInfo, error)
+	Truncate(size int64) error
+	Sync() error
 }
 
 // NSFile Name Server File descriptor
 type NSFile struct {
 	header *nsHeader // ns information
+	body             // parsed sections
+
+	bodyErr   error         // error of the first malformed line of the body, see BodyErr
+	malformed []DroppedLine // malformed lines of the body, reported by Validate
+
+	path     string // path the file was opened with, empty if not opened from disk
+	backups  int    // number of backups to keep, see WithBackups
+	backup   string // name of the last backup made by Save
+	pruneErr error  // error removing old backups by the last Save, see PruneErr
+
+	byteOrder binary.ByteOrder // byte order to write the size in, nil keeps the detected one
+
+	readOnly    bool          // file has no writable handle
+	lock        bool          // hold the exclusive lock on the file, see WithLock
+	lockTimeout time.Duration // how long to wait for the lock
+	writeCheck  WriteChecker  // check run before writing, see WithWriteCheck
 
 	nsDisker // file handle
 }
@@ -69,30 +91,82 @@ var (
 )
 
 var (
-	errNotInitialised  = errors.New("internal error: structure not initialised")
-	errNotSiebns       = errors.New("not a siebel gateway file")
-	errByteOrderNil    = errors.New("internal error: byteOrder is nil")
-	errZeroFilesize    = errors.New("zero file size")
-	errChecksumCorrupt = errors.New("Checksum part is corrupt.  Please fix it " +
-		"manually by\nopening the file in the editor and deleting " +
-		"data from line 4 (leaving the\nline 4 empty).")
+	errNotInitialised     = errors.New("internal error: structure not initialised")
+	errNotSiebns          = errors.New("not a siebel gateway file")
+	errByteOrderNil       = errors.New("internal error: byteOrder is nil")
+	errZeroFilesize       = errors.New("zero file size")
+	errInvalidUTF8        = errors.New("invalid UTF-8 sequence in the unicode file")
+	errMalformedLine      = errors.New("malformed line, expected section header or attribute")
+	errAttrOutsideSection = errors.New("attribute outside of any section")
+	errOrphanAttr         = errors.New("attribute of the malformed section header")
+	errTruncatedLine      = errors.New("truncated line with unbalanced quotes")
+	errNoType             = errors.New("section has no Type attribute")
+	errNoValue            = errors.New("section has no Value attribute")
+	errTypeMismatch       = errors.New("value type does not match the declared Type")
+	errInvalidValue       = errors.New("value is invalid for the declared Type")
+	errUnbalancedQuotes   = errors.New("unbalanced quotes in the value")
+	errInvalidPath        = errors.New("invalid section path")
+	errSectionNotFound    = errors.New("section not found")
+	errSectionExists      = errors.New("section already exists")
+	errAttrNotFound       = errors.New("attribute not found")
+	errNoParent           = errors.New("parent section does not exist")
+	errHasChildren        = errors.New("section has child sections")
+	errReadOnly           = errors.New("file is opened read-only")
+	errLocked             = errors.New("file is locked")
+	errLockUnsupported    = errors.New("file locking is not supported on this system")
+	errGatewayRunning     = errors.New("Siebel Gateway Name Server is running")
+	errReservedKey        = errors.New(`attribute names "` + keyChildren + `" and "` + keySection + `" are reserved for the export`)
+	errUnexpectedField    = errors.New("unexpected field")
+	errMissingField       = errors.New("missing field")
+	errFieldType          = errors.New("unexpected type of the field")
+	errInvalidName        = errors.New("invalid name")
+	errDuplicateName      = errors.New("duplicate name")
+	errLineBreak          = errors.New("line break in the value")
+	errUnknownByteOrder   = errors.New(`unknown byte order, expected "little" or "big"`)
+	errInvalidVersion     = errors.New("invalid version")
+	errUnsupportedFormat  = errors.New("unsupported nsfile format version")
+	errChecksumCorrupt    = errors.New("checksum line is corrupt, run \"siebnsfix repair\" to rebuild it")
+	errParamNotSet        = errors.New("parameter is not set at any level")
+	errEmptyMapping       = errors.New("mapping of the empty value")
 )
 
 // FixSize fixes the size in header regardless of whether
 // NSFile.CorrectionNeeded is true or false.  Sets NSFile.CorrectionNeeded to
 // false
 func (ns *NSFile) FixSize() (int, error) {
+	if err := ns.checkWritable(); err != nil {
+		return 0, err
+	}
+	if err := ns.checkWrite(); err != nil {
+		return 0, err
+	}
+	ns.applyByteOrder()
 	return ns.header.writeEncodedSize(ns, ns.Size())
 }
 
-// IsHeaderCorrect returns true if the file header doesn't need adjustment
+// IsHeaderCorrect returns true if the file header doesn't need adjustment:
+// the encoded size matches the file size and is in the byte order set by
+// SetByteOrder, if any.
 func (ns *NSFile) IsHeaderCorrect() bool {
 	size, _ := ns.header.readEncodedSize(ns)
+	if ns.byteOrder != nil && ns.byteOrder != ns.header.byteOrder {
+		return false
+	}
 	return (ns.Size() == size)
 }
 
 // Open opens existing nsfile
-func Open(path string) (*NSFile, error) {
+func Open(path string, opts ...Option) (*NSFile, error) {
+	ns, err := openFile(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	return ns, ns.load()
+}
+
+// openFile opens the file for writing, applying options, without loading
+// it.
+func openFile(path string, opts []Option) (*NSFile, error) {
 	fileInfo, err := os.Stat(path)
 	if err != nil {
 		return nil, err
@@ -102,18 +176,88 @@ func Open(path string) (*NSFile, error) {
 		return nil, errNotSiebns
 	}
 
-	f, err := os.OpenFile(path, os.O_RDWR, 0644)
+	open := func() (*os.File, error) {
+		return os.OpenFile(path, os.O_RDWR, 0644)
+	}
+	f, err := open()
 	if err != nil {
 		return nil, err
 	}
-	hdr, err := readHeader(f)
-
 	ns := &NSFile{
-		nsDisker: f,
-		header:   hdr,
+		path:       path,
+		writeCheck: DefaultWriteCheck,
+	}
+	for _, opt := range opts {
+		opt(ns)
+	}
+	if ns.lock {
+		f, err = lockFile(f, path, open, ns.lockTimeout)
+		switch err {
+		case nil:
+		case errLockUnsupported:
+			ns.lock = false // proceed unlocked
+		default:
+			f.Close()
+			return nil, err
+		}
 	}
+	ns.nsDisker = f
+	return ns, nil
+}
+
+// Name returns the name of the file.
+func (ns *NSFile) Name() string {
+	if ns.path != "" {
+		return ns.path
+	}
+	return ns.nsDisker.Name()
+}
+
+// load reads and parses the header and the body of the file.
+func (ns *NSFile) load() error {
+	if _, err := ns.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return ns.parse(ns)
+}
+
+// parse reads and parses the header and the body of the file from r.  The
+// malformed body does not fail the parsing, the well-formed sections are
+// kept for reading and validation, see BodyErr.
+func (ns *NSFile) parse(r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	rd := newLineReader(bytes.NewReader(data))
+	hdr, err := parseHeader(rd)
+	if err != nil {
+		return err
+	}
+	ns.header = hdr
+	ns.bodyErr, ns.malformed = nil, nil
+	b, err := parseBody(rd, hdr)
+	if lerr, ok := err.(*lineError); ok {
+		ns.bodyErr = lerr
+		rd = newLineReader(bytes.NewReader(data))
+		if _, err := parseHeader(rd); err != nil {
+			return err
+		}
+		b, err = parseLines(rd, hdr, &ns.malformed)
+	}
+	if err != nil {
+		return err
+	}
+	ns.body = *b
+	return nil
+}
 
-	return ns, err
+// BodyErr returns the error of the first malformed line of the body, or nil
+// if the body is well-formed.  The file with the malformed body can be read
+// and validated, and its encoded size can be fixed with FixSize, but it
+// can't be edited or written, as the malformed lines would be lost.
+func (ns *NSFile) BodyErr() error {
+	return ns.bodyErr
 }
 
 // Size returns the file size
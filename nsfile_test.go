@@ -74,6 +74,14 @@ func (d *fakeDisker) Seek(offset int64, whence int) (int64, error) {
 	return 0, nil
 }
 
+func (d *fakeDisker) Truncate(size int64) error {
+	return nil
+}
+
+func (d *fakeDisker) Sync() error {
+	return nil
+}
+
 type fakeFileInfo struct {
 	WantName    string
 	WantSize    int64
@@ -29,13 +29,20 @@ func hasDOSlineEndings(line []byte) bool {
 // readHeader parses the header in the reader returning the nsHeader
 // structure
 func readHeader(r io.Reader) (*nsHeader, error) {
-	rd := newLineReader(r)
+	return parseHeader(newLineReader(r))
+}
 
+// parseHeader parses the header lines from the line reader, leaving it
+// positioned at the start of the body.
+func parseHeader(rd lineReader) (*nsHeader, error) {
 	sigLine := rd.readline()
 	if rd.err() != nil {
 		return nil, rd.err()
 	}
 	isUnicode, textOffset := hasBOM(sigLine)
+	if len(sigLine) < textOffset+len(signature) {
+		return nil, errNotSiebns
+	}
 	fileSignature := sigLine[textOffset : textOffset+len(signature)]
 	if string(fileSignature) != signature {
 		return nil, errNotSiebns
@@ -0,0 +1,21 @@
+package siebns
+
+// Option is the option of Open.
+type Option func(*NSFile)
+
+// Backup retention values for WithBackups.
+const (
+	// KeepAllBackups makes Save keep every backup it has made.
+	KeepAllBackups = 0
+	// NoBackup disables backups.
+	NoBackup = -1
+)
+
+// WithBackups sets the number of the most recent backups of the file that
+// Save keeps, older backups are removed.  Pass KeepAllBackups to keep all
+// of them (the default), or NoBackup to make no backups at all.
+func WithBackups(keep int) Option {
+	return func(ns *NSFile) {
+		ns.backups = keep
+	}
+}
@@ -0,0 +1,152 @@
+package siebns
+
+import "sort"
+
+// Override is the parameter set at some level of the inheritance chain,
+// see EffectiveParam.
+type Override struct {
+	Param
+	// Inherited is the parameter the override takes precedence over, the
+	// one of the previous level of the chain that is set, or nil if there's
+	// none.
+	Inherited *Param
+	// Redundant is true if removing the override does not change the
+	// effective value of the parameter on any server, as it equals the
+	// inherited value.
+	Redundant bool
+}
+
+// AliasOverrides are the levels the parameter alias is set at.
+type AliasOverrides struct {
+	Alias     string
+	Overrides []Override // in the order of the file
+}
+
+// OverrideReport returns, for each parameter alias, every level it is set
+// at and the value it overrides, ordered by alias.  Parameters of component
+// groups and named subsystems are not the part of the inheritance chain,
+// they are reported, but never as redundant.
+func (ns *NSFile) OverrideReport() []AliasOverrides {
+	r := newOverrideResolver(ns.sections)
+	byAlias := make(map[string]*AliasOverrides)
+	var aliases []string
+	for _, prm := range ns.Params() {
+		ao, ok := byAlias[prm.Alias]
+		if !ok {
+			ao = &AliasOverrides{Alias: prm.Alias}
+			byAlias[prm.Alias] = ao
+			aliases = append(aliases, prm.Alias)
+		}
+		ao.Overrides = append(ao.Overrides, r.override(prm))
+	}
+	sort.Strings(aliases)
+	report := make([]AliasOverrides, len(aliases))
+	for i, alias := range aliases {
+		report[i] = *byAlias[alias]
+	}
+	return report
+}
+
+// RemoveRedundant removes the redundant overrides, see Override, and saves
+// the file.  The removed parameters are returned.  If there's nothing to
+// remove, the file is not saved.
+func (ns *NSFile) RemoveRedundant() ([]Param, error) {
+	// the override equals the inherited value, so removing it leaves the
+	// overrides that inherit from it redundant: all of them are found
+	// before removing any.
+	r := newOverrideResolver(ns.sections)
+	var redundant []Param
+	for _, prm := range ns.Params() {
+		if r.override(prm).Redundant {
+			redundant = append(redundant, prm)
+		}
+	}
+	if len(redundant) == 0 {
+		return nil, nil
+	}
+	var removed []Param
+	err := ns.edit(func() error {
+		for _, prm := range redundant {
+			if err := ns.deleteSection(prm.Path); err != nil {
+				return err
+			}
+			removed = append(removed, prm)
+		}
+		return nil
+	})
+	return removed, err
+}
+
+// overrideResolver resolves the inherited values of the parameters.
+type overrideResolver struct {
+	sections map[string]*Section
+	servers  map[string][]string // server names by enterprise
+}
+
+func newOverrideResolver(sections []*Section) *overrideResolver {
+	r := &overrideResolver{
+		sections: make(map[string]*Section, len(sections)),
+		servers:  make(map[string][]string),
+	}
+	for _, s := range sections {
+		if _, ok := r.sections[s.Path]; ok {
+			continue // the first one wins, as in NSFile.Section
+		}
+		r.sections[s.Path] = s
+		if elems := splitPath(s.Path); len(elems) == 4 && elems[0] == dirEnterprises && elems[2] == dirServers {
+			r.servers[elems[1]] = append(r.servers[elems[1]], elems[3])
+		}
+	}
+	return r
+}
+
+func (r *overrideResolver) section(p string) *Section {
+	return r.sections[p]
+}
+
+// override returns the override of the parameter.  The override is
+// redundant if it is redundant for every server it applies to: the
+// component parameter of the enterprise is inherited by the component of
+// each server, where the server parameter may come in between.
+func (r *overrideResolver) override(prm Param) Override {
+	o := Override{Param: prm}
+	var component string
+	servers := []string{prm.Server}
+	switch prm.Level {
+	case LevelDefinition, LevelEnterprise:
+		return o // nothing to inherit from
+	case LevelServer:
+	case LevelServerComponent:
+		component = prm.Object
+	case LevelComponent:
+		component = prm.Object
+		servers = append(servers, r.servers[prm.Enterprise]...)
+	default:
+		return o // not in the chain
+	}
+	o.Redundant = true
+	for i, server := range servers {
+		inherited := r.inherited(prm, server, component)
+		if i == 0 {
+			o.Inherited = inherited
+		}
+		if inherited == nil || inherited.Type != prm.Type || inherited.Value != prm.Value {
+			o.Redundant = false
+		}
+	}
+	return o
+}
+
+// inherited returns the parameter that prm overrides in the chain of the
+// component of the server, or nil if there's none.
+func (r *overrideResolver) inherited(prm Param, server, component string) *Param {
+	var prev *Param
+	for _, p := range resolveChain(r.section, prm.Enterprise, server, component, prm.Alias) {
+		if p.Path == prm.Path {
+			return prev
+		}
+		p := p
+		prev = &p
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package siebns
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// testfileOverrides is testfileEnterprise with the component parameters of
+// the enterprise: MaxTasks equals the value inherited on every server, and
+// Lang is overridden by the server app02.
+const testfileOverrides = testfileEnterprise + `[/enterprises/SBA_84/components]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/components/SCCObjMgr_enu]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/components/SCCObjMgr_enu/parameters]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/components/SCCObjMgr_enu/parameters/MaxTasks]
+	Persistence=full
+	Type=integer
+	Value=100
+[/enterprises/SBA_84/components/SCCObjMgr_enu/parameters/Lang]
+	Persistence=full
+	Type=string
+	Value="ENU"
+`
+
+// overrideSummary is the override without the parameter details.
+type overrideSummary struct {
+	Path      string
+	Inherited string // path of the inherited parameter
+	Redundant bool
+}
+
+func TestNSFile_OverrideReport(t *testing.T) {
+	ns, err := ParseBytes([]byte(testfileOverrides))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const ent = "/enterprises/SBA_84"
+	want := map[string][]overrideSummary{
+		"DSConnectString": {
+			{Path: ent + "/named subsystems/GatewayDataSrc/parameters/DSConnectString"},
+		},
+		"Host": {
+			{Path: ent + "/servers/app01/parameters/Host"},
+		},
+		"Lang": {
+			{Path: ent + "/parameters/Lang"},
+			{Path: ent + "/component definitions/SCCObjMgr_enu/parameters/Lang"},
+			{Path: ent + "/servers/app02/parameters/Lang", Inherited: ent + "/parameters/Lang"},
+			{Path: ent + "/components/SCCObjMgr_enu/parameters/Lang", Inherited: ent + "/parameters/Lang"},
+		},
+		"MaxTasks": {
+			{Path: ent + "/parameters/MaxTasks"},
+			{Path: ent + "/component definitions/SCCObjMgr_enu/parameters/MaxTasks"},
+			{Path: ent + "/servers/app01/parameters/MaxTasks", Inherited: ent + "/parameters/MaxTasks", Redundant: true},
+			{Path: ent + "/servers/app01/components/SCCObjMgr_enu/parameters/MaxTasks", Inherited: ent + "/components/SCCObjMgr_enu/parameters/MaxTasks"},
+			{Path: ent + "/components/SCCObjMgr_enu/parameters/MaxTasks", Inherited: ent + "/parameters/MaxTasks", Redundant: true},
+		},
+	}
+
+	got := make(map[string][]overrideSummary)
+	var aliases []string
+	for _, ao := range ns.OverrideReport() {
+		aliases = append(aliases, ao.Alias)
+		for _, o := range ao.Overrides {
+			s := overrideSummary{Path: o.Path, Redundant: o.Redundant}
+			if o.Inherited != nil {
+				s.Inherited = o.Inherited.Path
+			}
+			got[ao.Alias] = append(got[ao.Alias], s)
+		}
+	}
+	if diff := cmp.Diff([]string{"DSConnectString", "Host", "Lang", "MaxTasks"}, aliases); diff != "" {
+		t.Errorf("OverrideReport() aliases mismatch, (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("OverrideReport() mismatch, (-want,+got):\n%s", diff)
+	}
+}
+
+func TestNSFile_RemoveRedundant(t *testing.T) {
+	ns := parseTestNSFile(t, withValidSize(testfileOverrides))
+	defer CloseTestNSFile(ns)
+	ns.backups = NoBackup
+
+	removed, err := ns.RemoveRedundant()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const ent = "/enterprises/SBA_84"
+	var paths []string
+	for _, prm := range removed {
+		paths = append(paths, prm.Path)
+	}
+	wantRemoved := []string{
+		ent + "/servers/app01/parameters/MaxTasks",
+		ent + "/components/SCCObjMgr_enu/parameters/MaxTasks",
+	}
+	if diff := cmp.Diff(wantRemoved, paths); diff != "" {
+		t.Errorf("RemoveRedundant() mismatch, (-want,+got):\n%s", diff)
+	}
+	for _, p := range wantRemoved {
+		if ns.Section(p) != nil {
+			t.Errorf("section %s was not removed", p)
+		}
+	}
+	if !ns.IsHeaderCorrect() {
+		t.Error("IsHeaderCorrect() = false after RemoveRedundant")
+	}
+	// effective values are unchanged.
+	for _, server := range []string{"", "app01", "app02"} {
+		prm, _, err := ns.EffectiveParam("SBA_84", server, "SCCObjMgr_enu", "MaxTasks")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "100"
+		if server == "app01" {
+			want = "200"
+		}
+		if prm.Value != want {
+			t.Errorf("EffectiveParam(%q) = %s, want %s", server, prm.Value, want)
+		}
+	}
+	// nothing is left to remove, and the file is not written, so that it
+	// may as well be read-only.
+	ns.readOnly = true
+	if removed, err := ns.RemoveRedundant(); err != nil || len(removed) != 0 {
+		t.Errorf("second RemoveRedundant() = %v, %v, want nothing removed", removed, err)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package siebns
+
+import "os"
+
+// copyOwner is a no-op on systems without unix file ownership.
+func copyOwner(name string, fi os.FileInfo) {}
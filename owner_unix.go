@@ -0,0 +1,19 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package siebns
+
+import (
+	"os"
+	"syscall"
+)
+
+// copyOwner makes the owner of the file name the same as of fi.  Only the
+// privileged user may change the owner, so the errors are ignored.
+func copyOwner(name string, fi os.FileInfo) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return
+	}
+	os.Chown(name, int(st.Uid), int(st.Gid))
+}
@@ -0,0 +1,97 @@
+package siebns
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// Param is the parameter section of the configuration, see "Layout of the
+// gateway configuration".
+type Param struct {
+	Path        string // section path
+	Enterprise  string
+	Server      string // server name, for server and server component levels
+	Level       Level
+	Object      string // component, component group or named subsystem name
+	Alias       string // parameter alias
+	Type        string
+	Value       string // unquoted value of the string parameter
+	Persistence string
+}
+
+// newParam returns the parameter of the section s at the location loc.
+func newParam(s *Section, loc paramLocation) Param {
+	prm := Param{
+		Path:       s.Path,
+		Enterprise: loc.enterprise,
+		Server:     loc.server,
+		Level:      loc.level,
+		Object:     loc.object,
+		Alias:      loc.alias,
+		Type:       s.Type(),
+	}
+	prm.Value, _ = s.Get(keyValue)
+	prm.Persistence, _ = s.Get(keyPersistence)
+	if prm.Type == TypeString {
+		if v, err := unquote(prm.Value); err == nil {
+			prm.Value = v
+		}
+	}
+	return prm
+}
+
+// Params returns all parameters in the order of the file.
+func (ns *NSFile) Params() []Param {
+	var params []Param
+	for _, s := range ns.sections {
+		if loc, ok := parseParamPath(s.Path); ok {
+			params = append(params, newParam(s, loc))
+		}
+	}
+	return params
+}
+
+// NonDefault returns the parameters that differ from the enterprise-level
+// parameter with the same alias, or that have no enterprise-level
+// parameter.  Enterprise-level parameters are not returned.
+func NonDefault(params []Param) []Param {
+	defaults := make(map[string]Param)
+	for _, prm := range params {
+		if prm.Level == LevelEnterprise {
+			defaults[prm.Path] = prm
+		}
+	}
+	var nondef []Param
+	for _, prm := range params {
+		if prm.Level == LevelEnterprise {
+			continue
+		}
+		def, ok := defaults[paramPath(paramLocation{enterprise: prm.Enterprise, level: LevelEnterprise, alias: prm.Alias})]
+		if ok && def.Type == prm.Type && def.Value == prm.Value {
+			continue
+		}
+		nondef = append(nondef, prm)
+	}
+	return nondef
+}
+
+// paramsHeader is the header row of WriteParamsCSV.
+var paramsHeader = []string{"enterprise", "server", "level", "object", "alias", "type", "value", "persistence"}
+
+// WriteParamsCSV writes params to w as CSV with the header row, comma is
+// the field delimiter, i.e. '\t' for TSV.
+func WriteParamsCSV(w io.Writer, params []Param, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.Write(paramsHeader); err != nil {
+		return err
+	}
+	for _, prm := range params {
+		rec := []string{prm.Enterprise, prm.Server, prm.Level.String(), prm.Object, prm.Alias, prm.Type, prm.Value, prm.Persistence}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,225 @@
+package siebns
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// testfileEnterprise is the enterprise with two servers, see "Layout of the
+// gateway configuration".
+const testfileEnterprise = `Siebel Name Server Backing File
+16.0.0.0 [23057] ENU
+1.2
+DAMAAAAAAAA=             
+
+[/]
+	Persistence=partial
+	Type=empty
+[/enterprises]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/parameters]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/parameters/MaxTasks]
+	Persistence=full
+	Type=integer
+	Value=100
+[/enterprises/SBA_84/parameters/Lang]
+	Persistence=full
+	Type=string
+	Value="ENU"
+[/enterprises/SBA_84/component definitions]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/component definitions/SCCObjMgr_enu]
+	Persistence=full
+	Type=string
+	Value="Call Center Object Manager (ENU)"
+[/enterprises/SBA_84/component definitions/SCCObjMgr_enu/parameters]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/component definitions/SCCObjMgr_enu/parameters/MaxTasks]
+	Persistence=full
+	Type=integer
+	Value=20
+[/enterprises/SBA_84/component definitions/SCCObjMgr_enu/parameters/Lang]
+	Persistence=full
+	Type=string
+	Value="ENU"
+[/enterprises/SBA_84/component groups]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/component groups/CallCenter]
+	Persistence=full
+	Type=string
+	Value="Siebel Call Center"
+[/enterprises/SBA_84/named subsystems]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/named subsystems/GatewayDataSrc]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/named subsystems/GatewayDataSrc/parameters]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/named subsystems/GatewayDataSrc/parameters/DSConnectString]
+	Persistence=full
+	Type=string
+	Value="app01.example.com:2320"
+[/enterprises/SBA_84/servers]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/servers/app01]
+	Persistence=full
+	Type=string
+	Value="app01.example.com"
+[/enterprises/SBA_84/servers/app01/parameters]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/servers/app01/parameters/MaxTasks]
+	Persistence=full
+	Type=integer
+	Value=100
+[/enterprises/SBA_84/servers/app01/parameters/Host]
+	Persistence=full
+	Type=string
+	Value="app01.example.com"
+[/enterprises/SBA_84/servers/app01/components]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/servers/app01/components/SCCObjMgr_enu]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/servers/app01/components/SCCObjMgr_enu/parameters]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/servers/app01/components/SCCObjMgr_enu/parameters/MaxTasks]
+	Persistence=full
+	Type=integer
+	Value=200
+[/enterprises/SBA_84/servers/app01/component groups]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/servers/app01/component groups/CallCenter]
+	Persistence=full
+	Type=boolean
+	Value=True
+[/enterprises/SBA_84/servers/app02]
+	Persistence=full
+	Type=string
+	Value="app02.example.com"
+[/enterprises/SBA_84/servers/app02/parameters]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/servers/app02/parameters/Lang]
+	Persistence=full
+	Type=string
+	Value="DEU"
+`
+
+func TestNSFile_Params(t *testing.T) {
+	ns, err := ParseBytes([]byte(testfileEnterprise))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const ent = "/enterprises/SBA_84"
+	params := ns.Params()
+	want := []Param{
+		{Path: ent + "/parameters/MaxTasks", Enterprise: "SBA_84", Level: LevelEnterprise, Alias: "MaxTasks", Type: TypeInteger, Value: "100", Persistence: "full"},
+		{Path: ent + "/parameters/Lang", Enterprise: "SBA_84", Level: LevelEnterprise, Alias: "Lang", Type: TypeString, Value: "ENU", Persistence: "full"},
+		{Path: ent + "/component definitions/SCCObjMgr_enu/parameters/MaxTasks", Enterprise: "SBA_84", Level: LevelDefinition, Object: "SCCObjMgr_enu", Alias: "MaxTasks", Type: TypeInteger, Value: "20", Persistence: "full"},
+		{Path: ent + "/component definitions/SCCObjMgr_enu/parameters/Lang", Enterprise: "SBA_84", Level: LevelDefinition, Object: "SCCObjMgr_enu", Alias: "Lang", Type: TypeString, Value: "ENU", Persistence: "full"},
+		{Path: ent + "/named subsystems/GatewayDataSrc/parameters/DSConnectString", Enterprise: "SBA_84", Level: LevelNamedSubsystem, Object: "GatewayDataSrc", Alias: "DSConnectString", Type: TypeString, Value: "app01.example.com:2320", Persistence: "full"},
+		{Path: ent + "/servers/app01/parameters/MaxTasks", Enterprise: "SBA_84", Server: "app01", Level: LevelServer, Alias: "MaxTasks", Type: TypeInteger, Value: "100", Persistence: "full"},
+		{Path: ent + "/servers/app01/parameters/Host", Enterprise: "SBA_84", Server: "app01", Level: LevelServer, Alias: "Host", Type: TypeString, Value: "app01.example.com", Persistence: "full"},
+		{Path: ent + "/servers/app01/components/SCCObjMgr_enu/parameters/MaxTasks", Enterprise: "SBA_84", Server: "app01", Level: LevelServerComponent, Object: "SCCObjMgr_enu", Alias: "MaxTasks", Type: TypeInteger, Value: "200", Persistence: "full"},
+		{Path: ent + "/servers/app02/parameters/Lang", Enterprise: "SBA_84", Server: "app02", Level: LevelServer, Alias: "Lang", Type: TypeString, Value: "DEU", Persistence: "full"},
+	}
+	if diff := cmp.Diff(want, params); diff != "" {
+		t.Errorf("Params() mismatch, (-want,+got):\n%s", diff)
+	}
+
+	var nondef []string
+	for _, prm := range NonDefault(params) {
+		nondef = append(nondef, prm.Path)
+	}
+	wantNonDef := []string{
+		ent + "/component definitions/SCCObjMgr_enu/parameters/MaxTasks",
+		ent + "/named subsystems/GatewayDataSrc/parameters/DSConnectString",
+		ent + "/servers/app01/parameters/Host",
+		ent + "/servers/app01/components/SCCObjMgr_enu/parameters/MaxTasks",
+		ent + "/servers/app02/parameters/Lang",
+	}
+	if diff := cmp.Diff(wantNonDef, nondef); diff != "" {
+		t.Errorf("NonDefault() mismatch, (-want,+got):\n%s", diff)
+	}
+}
+
+func TestWriteParamsCSV(t *testing.T) {
+	params := []Param{
+		{Enterprise: "SBA_84", Level: LevelEnterprise, Alias: "MaxTasks", Type: TypeInteger, Value: "100", Persistence: "full"},
+		{Enterprise: "SBA_84", Server: "app01", Level: LevelServerComponent, Object: "SCCObjMgr_enu", Alias: "Title", Type: TypeString, Value: `a "b", c`},
+	}
+	tests := []struct {
+		name  string
+		comma rune
+		want  string
+	}{
+		{"csv", ',', "enterprise,server,level,object,alias,type,value,persistence\n" +
+			"SBA_84,,enterprise,,MaxTasks,integer,100,full\n" +
+			"SBA_84,app01,server component,SCCObjMgr_enu,Title,string,\"a \"\"b\"\", c\",\n"},
+		{"tsv", '\t', "enterprise\tserver\tlevel\tobject\talias\ttype\tvalue\tpersistence\n" +
+			"SBA_84\t\tenterprise\t\tMaxTasks\tinteger\t100\tfull\n" +
+			"SBA_84\tapp01\tserver component\tSCCObjMgr_enu\tTitle\tstring\t\"a \"\"b\"\", c\"\t\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteParamsCSV(&buf, params, tt.comma); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("WriteParamsCSV() mismatch, (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_parseParamPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want paramLocation
+		ok   bool
+	}{
+		{"/enterprises/E/parameters/A", paramLocation{enterprise: "E", level: LevelEnterprise, alias: "A"}, true},
+		{"/enterprises/E/servers/S/parameters/A", paramLocation{enterprise: "E", server: "S", level: LevelServer, alias: "A"}, true},
+		{"/enterprises/E/servers/S/components/C/parameters/A", paramLocation{enterprise: "E", server: "S", level: LevelServerComponent, object: "C", alias: "A"}, true},
+		{"/enterprises/E/components/C/parameters/A", paramLocation{enterprise: "E", level: LevelComponent, object: "C", alias: "A"}, true},
+		{"/enterprises/E/component groups/G/parameters/A", paramLocation{enterprise: "E", level: LevelComponentGroup, object: "G", alias: "A"}, true},
+		{"/enterprises/E/named subsystems/N/parameters/A", paramLocation{enterprise: "E", level: LevelNamedSubsystem, object: "N", alias: "A"}, true},
+		{"/enterprises/E/component definitions/C/parameters/A", paramLocation{enterprise: "E", level: LevelDefinition, object: "C", alias: "A"}, true},
+		{"/enterprises/E/parameters", paramLocation{}, false},
+		{"/enterprises/E/servers/S/component groups/G", paramLocation{}, false},
+		{"/enterprises/E/other/X/parameters/A", paramLocation{}, false},
+		{"/other/E/parameters/A", paramLocation{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := parseParamPath(tt.path)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("parseParamPath() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+			if ok {
+				if p := paramPath(got); p != tt.path {
+					t.Errorf("paramPath() = %q, want %q", p, tt.path)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package siebns
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// OpenReadOnly opens existing nsfile for reading only.  The file may reside
+// on the read-only mount or belong to the other user, as long as it is
+// readable.  Methods that write to the file return an error.
+func OpenReadOnly(path string) (*NSFile, error) {
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if fileInfo.Size() < headerSize {
+		return nil, errNotSiebns
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	ns := &NSFile{
+		nsDisker: &roDisker{f},
+		readOnly: true,
+	}
+
+	return ns, ns.load()
+}
+
+// Parse reads the file from r.  The returned NSFile is read-only, but it
+// can be written elsewhere with WriteTo.
+func Parse(r io.Reader) (*NSFile, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBytes(data)
+}
+
+// ParseBytes parses the file contents in data.  The returned NSFile is
+// read-only, but it can be written elsewhere with WriteTo.
+func ParseBytes(data []byte) (*NSFile, error) {
+	if len(data) < headerSize {
+		return nil, errNotSiebns
+	}
+	ns := &NSFile{
+		nsDisker: newMemDisker(data),
+		readOnly: true,
+	}
+	return ns, ns.load()
+}
+
+// checkWritable returns an error if the file is read-only.
+func (ns *NSFile) checkWritable() error {
+	if ns.readOnly {
+		return errReadOnly
+	}
+	return nil
+}
+
+// roDisker is the read-only file.  Write operations fail with errReadOnly.
+type roDisker struct {
+	*os.File
+}
+
+func (d *roDisker) Write(p []byte) (int, error) { return 0, errReadOnly }
+func (d *roDisker) Truncate(size int64) error   { return errReadOnly }
+func (d *roDisker) Sync() error                 { return errReadOnly }
+
+// memDisker is the read-only in-memory file.
+type memDisker struct {
+	*bytes.Reader
+	size int64
+}
+
+func newMemDisker(data []byte) *memDisker {
+	return &memDisker{Reader: bytes.NewReader(data), size: int64(len(data))}
+}
+
+func (d *memDisker) Write(p []byte) (int, error) { return 0, errReadOnly }
+func (d *memDisker) Truncate(size int64) error   { return errReadOnly }
+func (d *memDisker) Sync() error                 { return errReadOnly }
+func (d *memDisker) Close() error                { return nil }
+func (d *memDisker) Name() string                { return "" }
+
+func (d *memDisker) Stat() (os.FileInfo, error) {
+	return &memFileInfo{size: d.size}, nil
+}
+
+// memFileInfo describes the in-memory file.
+type memFileInfo struct {
+	size int64
+}
+
+func (fi *memFileInfo) Name() string       { return "" }
+func (fi *memFileInfo) Size() int64        { return fi.size }
+func (fi *memFileInfo) Mode() os.FileMode  { return 0444 }
+func (fi *memFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi *memFileInfo) IsDir() bool        { return false }
+func (fi *memFileInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,98 @@
+package siebns
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type errReader struct{}
+
+func (errReader) Read(p []byte) (int, error) { return 0, errors.New("read error") }
+
+func TestOpenReadOnly(t *testing.T) {
+	contents := withValidSize(testfileSample)
+	dir, name := createTestDir(t, contents)
+	defer os.RemoveAll(dir)
+	if err := os.Chmod(name, 0444); err != nil {
+		t.Fatal(err)
+	}
+
+	ns, err := OpenReadOnly(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ns.Close()
+
+	if !ns.IsHeaderCorrect() {
+		t.Error("IsHeaderCorrect() = false")
+	}
+	if len(ns.Sections()) != 5 {
+		t.Errorf("len(Sections()) = %d, want 5", len(ns.Sections()))
+	}
+	testReadOnly(t, ns, contents)
+}
+
+func TestParse(t *testing.T) {
+	contents := withValidSize(testfileSample)
+	tests := []struct {
+		name    string
+		parse   func() (*NSFile, error)
+		wantErr bool
+	}{
+		{"Parse", func() (*NSFile, error) { return Parse(strings.NewReader(contents)) }, false},
+		{"ParseBytes", func() (*NSFile, error) { return ParseBytes([]byte(contents)) }, false},
+		{"read error", func() (*NSFile, error) { return Parse(errReader{}) }, true},
+		{"too short", func() (*NSFile, error) { return ParseBytes([]byte(signature)) }, true},
+		{"corrupt", func() (*NSFile, error) { return ParseBytes([]byte(testfileCorrupt)) }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns, err := tt.parse()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			defer ns.Close()
+			if ns.Size() != int64(len(contents)) {
+				t.Errorf("Size() = %d, want %d", ns.Size(), len(contents))
+			}
+			if !ns.IsHeaderCorrect() {
+				t.Error("IsHeaderCorrect() = false")
+			}
+			testReadOnly(t, ns, contents)
+		})
+	}
+}
+
+// testReadOnly checks that write operations on ns fail and that ns can
+// still be written elsewhere.
+func testReadOnly(t *testing.T, ns *NSFile, contents string) {
+	const p = "/enterprises/SBA_84/parameters/MaxTasks"
+	if err := ns.SetParam(p, "Value", "200"); err != errReadOnly {
+		t.Errorf("SetParam() error = %v, want %v", err, errReadOnly)
+	}
+	if v, _ := ns.Section(p).Get("Value"); v != "100" {
+		t.Errorf("Value = %q after failed SetParam, want %q", v, "100")
+	}
+	if err := ns.Save(); err != errReadOnly {
+		t.Errorf("Save() error = %v, want %v", err, errReadOnly)
+	}
+	if _, err := ns.FixSize(); err != errReadOnly {
+		t.Errorf("FixSize() error = %v, want %v", err, errReadOnly)
+	}
+
+	var buf bytes.Buffer
+	if _, err := ns.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(contents, buf.String()); diff != "" {
+		t.Errorf("WriteTo() mismatch, (-want,+got):\n%s", diff)
+	}
+}
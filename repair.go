@@ -0,0 +1,118 @@
+package siebns
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"runtime"
+	"strings"
+)
+
+// Repair opens the file with the corrupt checksum line, rebuilds the line
+// and saves the file atomically, with the backup, as Save does.  The
+// checksum line is the line 4, following the version lines.  If it's
+// missing, so that the line 4 is the section header, the checksum line is
+// inserted.
+//
+// The byte order of the size is the one set by WithByteOrder, or the one of
+// the corrupt checksum, if it can be decoded, or the byte order of the
+// platform.
+//
+// The file with the valid checksum line is rewritten the same way.
+func Repair(path string, opts ...Option) (*NSFile, error) {
+	ns, err := openFile(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(ns)
+	if err != nil {
+		ns.Close()
+		return nil, err
+	}
+	repaired, bo, err := repairChecksum(data, ns.byteOrder)
+	if err != nil {
+		ns.Close()
+		return nil, err
+	}
+	ns.SetByteOrder(bo)
+	if err := ns.parse(bytes.NewReader(repaired)); err != nil {
+		ns.Close()
+		return nil, err
+	}
+	if err := ns.Save(); err != nil {
+		ns.Close()
+		return nil, err
+	}
+	return ns, nil
+}
+
+// repairChecksum returns data with the rebuilt checksum line and the byte
+// order of the size.  bo is the byte order to use, if nil, the byte order
+// of the corrupt checksum or of the platform is used.
+func repairChecksum(data []byte, bo binary.ByteOrder) ([]byte, binary.ByteOrder, error) {
+	rd := newLineReader(bytes.NewReader(data))
+	sigLine := rd.readline()
+	_, textOffset := hasBOM(sigLine)
+	if !bytes.HasPrefix(sigLine[textOffset:], []byte(signature)) {
+		return nil, nil, errNotSiebns
+	}
+	rd.readline() // siebel version
+	rd.readline() // nsfile version
+	if rd.err() != nil {
+		return nil, nil, errNotSiebns
+	}
+	start := rd.position()
+	line := rd.readline()
+	rest := data[rd.position():]
+	if bytes.HasPrefix(bytes.TrimSpace(line), []byte("[")) {
+		rest = data[start:] // the checksum line is missing
+	}
+
+	if bo == nil {
+		bo = nativeByteOrder()
+		if cksum := strings.TrimSpace(string(line)); len(cksum) == checksumSz {
+			if _, order, err := decodeSize([]byte(cksum)); err == nil {
+				bo = order
+			}
+		}
+	}
+	eol := string(crlf[1:])
+	if hasDOSlineEndings(sigLine) {
+		eol = string(crlf)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(data[:start])
+	buf.WriteString(strings.Repeat(" ", checksumWidth) + eol)
+	buf.Write(rest)
+	repaired := buf.Bytes()
+	size, err := encodeSize(int64(len(repaired)), bo)
+	if err != nil {
+		return nil, nil, err
+	}
+	copy(repaired[start:], size)
+	return repaired, bo, nil
+}
+
+// bigEndianArchs are the big endian values of runtime.GOARCH.
+var bigEndianArchs = map[string]bool{
+	"armbe":     true,
+	"arm64be":   true,
+	"mips":      true,
+	"mips64":    true,
+	"mips64p32": true,
+	"ppc":       true,
+	"ppc64":     true,
+	"s390":      true,
+	"s390x":     true,
+	"sparc":     true,
+	"sparc64":   true,
+}
+
+// nativeByteOrder returns the byte order of the platform.
+func nativeByteOrder() binary.ByteOrder {
+	if bigEndianArchs[runtime.GOARCH] {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}
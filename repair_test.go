@@ -0,0 +1,72 @@
+package siebns
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRepair(t *testing.T) {
+	const cksum = "DAMAAAAAAAA=             \n"
+	header := testfileSample[:strings.Index(testfileSample, cksum)]
+	body := testfileSample[len(header)+len(cksum):] // starts with the blank line
+	big := strings.Replace(testfileSample, "DAMAAAAAAAA=", "AAAAAAAAAww=", 1)
+	native := testfileSample
+	if nativeByteOrder() == binary.BigEndian {
+		native = big
+	}
+	dos := strings.Replace(testfileSample, "\n", "\r\n", -1)
+
+	tests := []struct {
+		name     string
+		contents string
+		bo       binary.ByteOrder
+		want     string
+		wantErr  error
+	}{
+		{"unpadded", header + "DAMAAAAAAAA=\n" + body, nil, testfileSample, nil},
+		{"unpadded big", header + "AAAAAAAAAww=\n" + body, nil, big, nil},
+		{"garbage", header + "#$%\n" + body, nil, native, nil},
+		{"garbage to big", header + "#$%\n" + body, binary.BigEndian, big, nil},
+		{"short", header + "AAAA                     \n" + body, nil, native, nil},
+		{"short padded", header + "DAMAAAAAAA==             \n" + body, nil, native, nil},
+		{"emptied", header + "\n" + body, nil, native, nil},
+		{"missing", header + body[1:], nil, strings.Replace(native, "=             \n\n", "=             \n", 1), nil},
+		{"dos", strings.Replace(header+"DAMAAAAAAAA=\n"+body, "\n", "\r\n", -1), nil, dos, nil},
+		{"valid", withValidSize(testfileSample), binary.BigEndian, big, nil},
+		{"not siebns", strings.Replace(testfileSample, "Siebel", "Oracle", 1), nil, "", errNotSiebns},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, name := createTestDir(t, tt.contents)
+			defer os.RemoveAll(dir)
+
+			ns, err := Repair(name, WithByteOrder(tt.bo))
+			if err != tt.wantErr {
+				t.Fatalf("Repair() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer ns.Close()
+			got, err := ioutil.ReadFile(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(withValidSize(tt.want), string(got)); diff != "" {
+				t.Errorf("repaired file mismatch, (-want,+got):\n%s", diff)
+			}
+			backup, err := ioutil.ReadFile(ns.Backup())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(backup) != tt.contents {
+				t.Error("backup does not match the original file")
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package siebns
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// DroppedLine is the line of the damaged file dropped by Salvage.
+type DroppedLine struct {
+	Line int    // line number
+	Text string // line without the line ending
+	Err  error  // why the line was dropped
+}
+
+func (d DroppedLine) String() string {
+	return fmt.Sprintf("line %d: %s: %q", d.Line, d.Err, d.Text)
+}
+
+// Salvage reads the damaged file, i.e. truncated or partially overwritten,
+// from r.  Unlike Parse, it doesn't stop at the malformed line, it drops it
+// and continues, so that every well-formed section is recovered.  Dropped
+// lines are returned along with the file, in the order of the file, with
+// their line numbers in the damaged file:
+//
+//   - malformed lines, and lines that can't be read;
+//   - lines with invalid UTF-8 sequences in the unicode file;
+//   - attributes outside of any section;
+//   - attributes following the malformed section header, that would
+//     otherwise become attributes of the previous section;
+//   - the last line without the line ending, that has unbalanced quotes,
+//     as the file was truncated in the middle of the value;
+//   - the corrupt checksum line, that is rebuilt as Repair does.
+//
+// The returned NSFile is read-only, use WriteTo to write the cleaned file.
+// The error is returned if the header can't be recovered.
+func Salvage(r io.Reader) (*NSFile, []DroppedLine, error) {
+	// the data read before the read error is salvaged.
+	data, readErr := ioutil.ReadAll(r)
+	var (
+		dropped []DroppedLine
+		shift   int // lines inserted before the body
+	)
+	if _, err := readHeader(bytes.NewReader(data)); err != nil {
+		repaired, _, rerr := repairChecksum(data, nil)
+		if rerr != nil {
+			if readErr != nil {
+				return nil, nil, readErr
+			}
+			return nil, nil, err
+		}
+		line := checksumLine(data)
+		if bytes.HasPrefix([]byte(line), []byte("[")) {
+			line = "" // the checksum line is missing
+			shift = 1
+		}
+		dropped = append(dropped, DroppedLine{Line: 4, Text: line, Err: errChecksumCorrupt})
+		data = repaired
+	}
+
+	rd := newLineReader(bytes.NewReader(data))
+	hdr, err := parseHeader(rd)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := parseLines(rd, hdr, &dropped)
+	if err != nil {
+		return nil, nil, err
+	}
+	if readErr != nil {
+		dropped = append(dropped, DroppedLine{Line: rd.lineno() + 1, Err: readErr})
+	}
+	for i := range dropped {
+		if dropped[i].Line > 4 {
+			dropped[i].Line -= shift
+		}
+	}
+	ns := &NSFile{
+		header:   hdr,
+		body:     *b,
+		nsDisker: newMemDisker(data),
+		readOnly: true,
+	}
+	return ns, dropped, nil
+}
+
+// checksumLine returns the line 4 of data without the line ending.
+func checksumLine(data []byte) string {
+	rd := newLineReader(bytes.NewReader(data))
+	for i := 0; i < 3; i++ {
+		rd.readline()
+	}
+	return string(trimEOL(rd.readline()))
+}
@@ -0,0 +1,118 @@
+package siebns
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// failReader fails with err.
+type failReader struct {
+	err error
+}
+
+func (r failReader) Read(p []byte) (int, error) { return 0, r.err }
+
+func TestSalvage(t *testing.T) {
+	errDisk := errors.New("disk error")
+	tests := []struct {
+		name        string
+		r           io.Reader
+		want        string
+		wantDropped []DroppedLine
+		wantErr     error
+	}{
+		{"valid",
+			strings.NewReader(testfileSample),
+			testfileSample,
+			nil, nil},
+		{"truncated",
+			strings.NewReader(testfileSample[:len(testfileSample)-len("lue=100\n")]),
+			testfileSample[:len(testfileSample)-len("\tValue=100\n")],
+			[]DroppedLine{{Line: 22, Text: "\tVa", Err: errMalformedLine}}, nil},
+		{"garbled",
+			strings.NewReader(strings.NewReplacer(
+				"[/enterprises/SBA_84]\n", "[/enterprises/SBA_84\x00\x00\n",
+				"[/enterprises/SBA_84/parameters]\n\tPersistence=full\n", "[/enterprises/SBA_84/parameters]\n\x00\x00\x00\n\tPersistence=full\n",
+			).Replace(testfileSample)),
+			strings.NewReplacer(
+				"[/enterprises/SBA_84]\n\tPersistence=full\n\tType=string\n\tValue=\"Siebel Enterprise\"\n", "",
+			).Replace(testfileSample),
+			[]DroppedLine{
+				{Line: 12, Text: "[/enterprises/SBA_84\x00\x00", Err: errMalformedLine},
+				{Line: 13, Text: "\tPersistence=full", Err: errOrphanAttr},
+				{Line: 14, Text: "\tType=string", Err: errOrphanAttr},
+				{Line: 15, Text: "\tValue=\"Siebel Enterprise\"", Err: errOrphanAttr},
+				{Line: 17, Text: "\x00\x00\x00", Err: errMalformedLine},
+			}, nil},
+		{"corrupt checksum",
+			strings.NewReader(strings.Replace(testfileSample, "DAMAAAAAAAA=             \n\n", "DAMAAAAAAAA=\n\tType=empty\n", 1)),
+			strings.Replace(testfileSample, "DAMAAAAAAAA=             \n\n", "DAMAAAAAAAA=             \n", 1),
+			[]DroppedLine{
+				{Line: 4, Text: "DAMAAAAAAAA=", Err: errChecksumCorrupt},
+				{Line: 5, Text: "\tType=empty", Err: errAttrOutsideSection},
+			}, nil},
+		{"short checksum",
+			strings.NewReader(strings.Replace(testfileSample, "DAMAAAAAAAA=             ", "AAAA                     ", 1)),
+			testfileSample,
+			[]DroppedLine{{Line: 4, Text: "AAAA                     ", Err: errChecksumCorrupt}}, nil},
+		{"short padded checksum",
+			strings.NewReader(strings.Replace(testfileSample, "DAMAAAAAAAA=             ", "DAMAAAAAAA==             ", 1)),
+			testfileSample,
+			[]DroppedLine{{Line: 4, Text: "DAMAAAAAAA==             ", Err: errChecksumCorrupt}}, nil},
+		{"missing checksum",
+			strings.NewReader(strings.NewReplacer(
+				"DAMAAAAAAAA=             \n\n", "",
+				"[/enterprises]\n", "[/enterprises]\n\x00\n",
+			).Replace(testfileSample)),
+			strings.Replace(testfileSample, "DAMAAAAAAAA=             \n\n", "DAMAAAAAAAA=             \n", 1),
+			[]DroppedLine{
+				{Line: 4, Text: "", Err: errChecksumCorrupt},
+				{Line: 8, Text: "\x00", Err: errMalformedLine},
+			}, nil},
+		{"truncated value",
+			strings.NewReader(testfileSample + "[/x]\n\tType=empty\n\tDescription=\"trunc"),
+			testfileSample + "[/x]\n\tType=empty\n",
+			[]DroppedLine{{Line: 25, Text: "\tDescription=\"trunc", Err: errTruncatedLine}}, nil},
+		{"read error",
+			io.MultiReader(strings.NewReader(testfileSample), failReader{errDisk}),
+			testfileSample,
+			[]DroppedLine{{Line: 23, Err: errDisk}}, nil},
+		{"not siebns",
+			strings.NewReader("garbage\n"),
+			"", nil, errNotSiebns},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns, dropped, err := Salvage(tt.r)
+			if err != tt.wantErr {
+				t.Fatalf("Salvage() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.wantDropped, dropped, cmp.Comparer(func(a, b error) bool { return a == b })); diff != "" {
+				t.Errorf("Salvage() dropped mismatch, (-want,+got):\n%s", diff)
+			}
+			var buf bytes.Buffer
+			if _, err := ns.WriteTo(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(withValidSize(tt.want), buf.String()); diff != "" {
+				t.Errorf("salvaged file mismatch, (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDroppedLine_String(t *testing.T) {
+	d := DroppedLine{Line: 7, Text: "\tVa", Err: errMalformedLine}
+	want := `line 7: malformed line, expected section header or attribute: "\tVa"`
+	if got := d.String(); got != want {
+		t.Errorf("DroppedLine.String() = %q, want %q", got, want)
+	}
+}
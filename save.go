@@ -0,0 +1,266 @@
+package siebns
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// backupTimeFmt is the timestamp format of the backup file name, Siebel
+// names backups as siebns.dat_YYYYMMDD_HHMMSS.
+const backupTimeFmt = "20060102_150405"
+
+// timeNow returns the current time, it is replaced in tests.
+var timeNow = time.Now
+
+// removeFile removes the file, it is replaced in tests.
+var removeFile = os.Remove
+
+// Save writes the whole file, recomputing the encoded size.
+//
+// The file opened with Open is saved atomically: the contents are written
+// to the temporary file in the same directory, which then replaces the
+// original file.  Before that, the backup of the original file is made
+// (see WithBackups).  Crash at any point leaves either the original or
+// the new file in place, never a partially written one.  Old backups are
+// removed after the file is replaced, failing to remove them does not fail
+// Save, see PruneErr.
+//
+// Files that were not opened from disk are overwritten in place.
+//
+// Before writing, the write check is run (see WithWriteCheck).
+func (ns *NSFile) Save() error {
+	if err := ns.checkWritable(); err != nil {
+		return err
+	}
+	if err := ns.checkWrite(); err != nil {
+		return err
+	}
+	data, err := ns.render()
+	if err != nil {
+		return err
+	}
+	if ns.path == "" {
+		return ns.saveInPlace(data)
+	}
+	return ns.saveAtomic(data)
+}
+
+// Backup returns the name of the last backup made by Save, or an empty
+// string if there was none.
+func (ns *NSFile) Backup() string {
+	return ns.backup
+}
+
+// PruneErr returns the error removing the old backups (see WithBackups) by
+// the last Save, or nil if there was none.  The file is saved regardless.
+func (ns *NSFile) PruneErr() error {
+	return ns.pruneErr
+}
+
+// saveInPlace overwrites the file handle with data.
+func (ns *NSFile) saveInPlace(data []byte) error {
+	if _, err := ns.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := ns.Write(data); err != nil {
+		return err
+	}
+	if err := ns.Truncate(int64(len(data))); err != nil {
+		return err
+	}
+	return ns.Sync()
+}
+
+// saveAtomic writes data to the temporary file, makes the backup of the
+// original and replaces it with the temporary file.
+func (ns *NSFile) saveAtomic(data []byte) error {
+	fi, err := os.Stat(ns.path)
+	if err != nil {
+		return err
+	}
+	dir, base := filepath.Split(ns.path)
+	tmp, err := ioutil.TempFile(dir, base+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after the successful rename
+
+	if err := ns.replaceWith(tmp, fi, data); err != nil {
+		tmp.Close()
+		return err
+	}
+	// the file is replaced, so that the error must not fail Save.
+	ns.pruneErr = nil
+	if ns.backups > 0 {
+		ns.pruneErr = pruneBackups(ns.path, ns.backups)
+	}
+	return nil
+}
+
+// replaceWith writes data to the temporary file tmp and renames it over the
+// original file with the mode and the owner of fi.  On success, tmp becomes
+// the file handle of ns.
+func (ns *NSFile) replaceWith(tmp *os.File, fi os.FileInfo, data []byte) error {
+	if _, err := tmp.Write(data); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(fi.Mode().Perm()); err != nil {
+		return err
+	}
+	// the file is usually owned by the Siebel service user, and if it is
+	// saved by root, the gateway would be unable to write it.
+	copyOwner(tmp.Name(), fi)
+	if ns.lock {
+		// the lock must be in place before the file appears under the
+		// original name.
+		if err := tryLock(tmp); err != nil {
+			return err
+		}
+	}
+
+	if ns.backups != NoBackup {
+		backup, err := makeBackup(ns.path)
+		if err != nil {
+			return err
+		}
+		ns.backup = backup
+	}
+	if err := os.Rename(tmp.Name(), ns.path); err != nil {
+		return err
+	}
+	syncDir(filepath.Dir(ns.path))
+
+	ns.nsDisker.Close()
+	ns.nsDisker = tmp
+	return nil
+}
+
+// syncDir flushes the directory entry changes to disk.  Not every system
+// allows it, so the errors are ignored.
+func syncDir(dir string) {
+	if dir == "" {
+		dir = "."
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
+
+// makeBackup makes the backup of the file and returns its name.  The backup
+// is the hard link to the original file, the original is copied if the
+// link can't be made.  If the backup with the current timestamp already
+// exists, a numeric suffix is added.
+func makeBackup(path string) (string, error) {
+	name := path + "_" + timeNow().Format(backupTimeFmt)
+	backup := name
+	for n := 1; ; n++ {
+		if _, err := os.Lstat(backup); os.IsNotExist(err) {
+			break
+		}
+		backup = name + "_" + strconv.Itoa(n)
+	}
+	if err := os.Link(path, backup); err == nil {
+		return backup, nil
+	}
+	return backup, copyFile(backup, path)
+}
+
+func copyFile(dst, src string) error {
+	fi, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fi.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// backupFile is the backup file name with its parsed timestamp.
+type backupFile struct {
+	name string
+	ts   time.Time
+	n    int // numeric suffix
+}
+
+// listBackups returns the backups of the file, oldest first.
+func listBackups(path string) ([]backupFile, error) {
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var backups []backupFile
+	prefix := base + "_"
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), prefix) {
+			continue
+		}
+		suffix := strings.TrimPrefix(e.Name(), prefix)
+		if len(suffix) < len(backupTimeFmt) {
+			continue
+		}
+		ts, err := time.ParseInLocation(backupTimeFmt, suffix[:len(backupTimeFmt)], time.Local)
+		if err != nil {
+			continue
+		}
+		var n int
+		if rest := suffix[len(backupTimeFmt):]; rest != "" {
+			if n, err = strconv.Atoi(strings.TrimPrefix(rest, "_")); err != nil || rest[0] != '_' {
+				continue
+			}
+		}
+		backups = append(backups, backupFile{name: filepath.Join(dir, e.Name()), ts: ts, n: n})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].ts.Equal(backups[j].ts) {
+			return backups[i].n < backups[j].n
+		}
+		return backups[i].ts.Before(backups[j].ts)
+	})
+	return backups, nil
+}
+
+// pruneBackups removes all but keep most recent backups of the file.
+func pruneBackups(path string, keep int) error {
+	backups, err := listBackups(path)
+	if err != nil {
+		return err
+	}
+	for len(backups) > keep {
+		if err := removeFile(backups[0].name); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package siebns
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// createTestDir creates the temporary directory with the siebns.dat file
+// with contents and returns the directory and the file name.
+func createTestDir(t *testing.T, contents string) (string, string) {
+	dir, err := ioutil.TempDir("", "siebns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(dir, "siebns.dat")
+	if err := ioutil.WriteFile(name, []byte(contents), 0640); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return dir, name
+}
+
+// fixTime makes timeNow return t, returns the function restoring it.
+func fixTime(t time.Time) func() {
+	timeNow = func() time.Time { return t }
+	return func() { timeNow = time.Now }
+}
+
+// dirNames returns the names of the files in dir.
+func dirNames(t *testing.T, dir string) []string {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestNSFile_SaveAtomic(t *testing.T) {
+	orig := withValidSize(testfileSample)
+	dir, name := createTestDir(t, orig)
+	defer os.RemoveAll(dir)
+	defer fixTime(time.Date(2018, 1, 15, 20, 59, 18, 0, time.Local))()
+
+	ns, err := Open(name, WithBackups(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ns.Close()
+
+	const p = "/enterprises/SBA_84/parameters/MaxTasks"
+	if err := ns.SetParam(p, "Value", "200"); err != nil {
+		t.Fatal(err)
+	}
+	if ns.Backup() != name+"_20180115_205918" {
+		t.Errorf("Backup() = %q, want %q", ns.Backup(), name+"_20180115_205918")
+	}
+	backup, err := ioutil.ReadFile(ns.Backup())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(orig, string(backup)); diff != "" {
+		t.Errorf("backup mismatch, (-want,+got):\n%s", diff)
+	}
+	if !ns.IsHeaderCorrect() {
+		t.Error("IsHeaderCorrect() = false after save")
+	}
+	if ns.Name() != name {
+		t.Errorf("Name() = %q, want %q", ns.Name(), name)
+	}
+	fi, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0640 {
+		t.Errorf("file mode = %v, want %v", fi.Mode().Perm(), os.FileMode(0640))
+	}
+
+	// two more saves within the same second, the oldest backup goes.
+	if err := ns.SetParam(p, "Value", "300"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ns.SetParam(p, "Value", "400"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"siebns.dat", "siebns.dat_20180115_205918_1", "siebns.dat_20180115_205918_2"}
+	if diff := cmp.Diff(want, dirNames(t, dir)); diff != "" {
+		t.Errorf("directory mismatch, (-want,+got):\n%s", diff)
+	}
+
+	reopened, err := Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	if v, _ := reopened.Section(p).Get("Value"); v != "400" {
+		t.Errorf("Value = %q, want %q", v, "400")
+	}
+}
+
+func TestNSFile_SaveNoBackup(t *testing.T) {
+	dir, name := createTestDir(t, withValidSize(testfileSample))
+	defer os.RemoveAll(dir)
+
+	ns, err := Open(name, WithBackups(NoBackup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ns.Close()
+
+	if err := ns.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if ns.Backup() != "" {
+		t.Errorf("Backup() = %q, want none", ns.Backup())
+	}
+	if diff := cmp.Diff([]string{"siebns.dat"}, dirNames(t, dir)); diff != "" {
+		t.Errorf("directory mismatch, (-want,+got):\n%s", diff)
+	}
+}
+
+func TestNSFile_SavePruneError(t *testing.T) {
+	dir, name := createTestDir(t, withValidSize(testfileSample))
+	defer os.RemoveAll(dir)
+	defer fixTime(time.Date(2018, 1, 15, 20, 59, 18, 0, time.Local))()
+	old := name + "_20170101_000000"
+	if err := ioutil.WriteFile(old, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	errRemove := errors.New("remove failed")
+	removeFile = func(string) error { return errRemove }
+	defer func() { removeFile = os.Remove }()
+
+	ns, err := Open(name, WithBackups(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ns.Close()
+
+	// the file is replaced before pruning, so that the change is kept.
+	const p = "/enterprises/SBA_84/parameters/MaxTasks"
+	if err := ns.SetParam(p, "Value", "200"); err != nil {
+		t.Fatalf("SetParam() error = %v", err)
+	}
+	if err := ns.PruneErr(); err != errRemove {
+		t.Errorf("PruneErr() = %v, want %v", err, errRemove)
+	}
+	if v, _ := ns.Section(p).Get("Value"); v != "200" {
+		t.Errorf("Value = %q, want %q", v, "200")
+	}
+	want := []string{"siebns.dat", "siebns.dat_20170101_000000", "siebns.dat_20180115_205918"}
+	if diff := cmp.Diff(want, dirNames(t, dir)); diff != "" {
+		t.Errorf("directory mismatch, (-want,+got):\n%s", diff)
+	}
+
+	removeFile = os.Remove
+	if err := ns.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ns.PruneErr(); err != nil {
+		t.Errorf("PruneErr() = %v after the successful Save", err)
+	}
+}
+
+func Test_listBackups(t *testing.T) {
+	dir, name := createTestDir(t, "")
+	defer os.RemoveAll(dir)
+
+	for _, n := range []string{
+		"siebns.dat_20180115_205918_10",
+		"siebns.dat_20180115_205918",
+		"siebns.dat_20180115_205918_2",
+		"siebns.dat_20170101_000000",
+		"siebns.dat_notabackup",
+		"siebns.dat_20180115_205918x",
+		"siebns.dat.tmp123",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, n), nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	backups, err := listBackups(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, b := range backups {
+		got = append(got, filepath.Base(b.name))
+	}
+	want := []string{"siebns.dat_20170101_000000", "siebns.dat_20180115_205918", "siebns.dat_20180115_205918_2", "siebns.dat_20180115_205918_10"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("listBackups() mismatch, (-want,+got):\n%s", diff)
+	}
+}
@@ -0,0 +1,302 @@
+package siebns
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CloneOptions are the options of CloneServer.
+type CloneOptions struct {
+	// Values maps the host-specific values of the source server to the
+	// values of the new one, i.e. "app01.example.com" to
+	// "app03.example.com".  Every occurrence in the Value attributes of the
+	// cloned sections is replaced, the longer ones first.
+	Values map[string]string
+	// Overwrite allows replacing the existing server, otherwise CloneServer
+	// fails if the server exists.
+	Overwrite bool
+}
+
+// CloneServer copies the configuration of the server from of the enterprise
+// to the server to: the server parameters, components with their parameters
+// and component group assignments, and saves the file.  Host-specific
+// values are rewritten according to opts.Values.
+func (ns *NSFile) CloneServer(enterprise, from, to string, opts CloneOptions) error {
+	if err := checkName(to); err != nil {
+		return &sectionError{serverPath(enterprise, to), err}
+	}
+	replacer, err := valueReplacer(opts.Values)
+	if err != nil {
+		return err
+	}
+	src, dst := serverPath(enterprise, from), serverPath(enterprise, to)
+	return ns.edit(func() error {
+		if src == dst {
+			return &sectionError{dst, errSectionExists}
+		}
+		if opts.Overwrite && ns.body.index(dst) >= 0 {
+			if ns.body.index(src) < 0 {
+				return &sectionError{src, errSectionNotFound}
+			}
+			if err := ns.body.deleteSubtree(dst); err != nil {
+				return err
+			}
+		}
+		return ns.body.copySubtree(src, dst, replacer.Replace)
+	})
+}
+
+// checkName returns an error if name can't be the name of the object.
+func checkName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/[]\r\n") {
+		return errInvalidName
+	}
+	return nil
+}
+
+// valueReplacer returns the replacer of the values, longer values are
+// replaced first, so that "app01.example.com" is not broken by the mapping
+// of "app01".
+func valueReplacer(values map[string]string) (*strings.Replacer, error) {
+	olds := make([]string, 0, len(values))
+	for old, new := range values {
+		if old == "" {
+			return nil, errEmptyMapping
+		}
+		if strings.ContainsAny(new, "\r\n") {
+			return nil, &fieldError{strconv.Quote(old), errLineBreak}
+		}
+		olds = append(olds, old)
+	}
+	sort.Slice(olds, func(i, j int) bool {
+		if len(olds[i]) != len(olds[j]) {
+			return len(olds[i]) > len(olds[j])
+		}
+		return olds[i] < olds[j]
+	})
+	pairs := make([]string, 0, 2*len(olds))
+	for _, old := range olds {
+		pairs = append(pairs, old, values[old])
+	}
+	return strings.NewReplacer(pairs...), nil
+}
+
+// RemoveOptions are the options of RemoveServer.
+type RemoveOptions struct {
+	// RemoveReferences removes the references to the server, see
+	// Reference, otherwise they are only reported.
+	RemoveReferences bool
+	// DryRun returns the summary without changing the file.
+	DryRun bool
+}
+
+// Reference is the section or the attribute outside of the server subtree
+// that mentions the server: the section of the server in the servers
+// directory of another object, i.e. the assignment of the server to the
+// component group, or the attribute value with the server name as a word,
+// i.e. the load balancing list "1:app01:2321;".
+type Reference struct {
+	Path  string // section path
+	Key   string // attribute, empty if the section path mentions the server
+	Value string // attribute value
+	// Removed is true if the reference is removed: the section is removed
+	// with its subtree, or the server is removed from the list value, and
+	// New is the new value.  The value that is not a list, i.e. the host
+	// name, is never removed.
+	Removed bool
+	New     string
+}
+
+// String returns the reference in the form "[path] Key=value", followed by
+// " -> new" if it is removed, or "[path]", followed by " (removed)".
+func (r Reference) String() string {
+	if r.Key == "" {
+		s := "[" + r.Path + "]"
+		if r.Removed {
+			s += " (removed)"
+		}
+		return s
+	}
+	s := "[" + r.Path + "] " + r.Key + "=" + r.Value
+	if r.Removed {
+		s += " -> " + r.New
+	}
+	return s
+}
+
+// RemoveSummary is what RemoveServer removed or would remove.
+type RemoveSummary struct {
+	Sections   []string    // sections of the server subtree
+	References []Reference // references to the server in the enterprise
+}
+
+// RemoveServer removes the server of the enterprise with its subtree and
+// saves the file.  The references to the server elsewhere in the
+// enterprise are found and removed if opts.RemoveReferences is set.  The
+// summary of the removed sections and found references is returned, with
+// opts.DryRun it is returned without changing the file.
+func (ns *NSFile) RemoveServer(enterprise, server string, opts RemoveOptions) (*RemoveSummary, error) {
+	srv := serverPath(enterprise, server)
+	if ns.body.index(srv) < 0 {
+		return nil, &sectionError{srv, errSectionNotFound}
+	}
+	sum := &RemoveSummary{}
+	removed := []string{srv} // roots of the removed subtrees
+	for _, s := range ns.sections {
+		if inSubtree(s.Path, srv) {
+			sum.Sections = append(sum.Sections, s.Path)
+		}
+	}
+	refs, edits := serverReferences(ns.sections, enterprise, server)
+	for i := range refs {
+		ref := &refs[i]
+		if !opts.RemoveReferences {
+			continue
+		}
+		if ref.Key == "" {
+			ref.Removed = true
+			removed = append(removed, ref.Path)
+		} else if ref.New, ref.Removed = withoutName(ref.Value, server); !ref.Removed {
+			ref.New = ""
+		}
+	}
+	sum.References = refs
+	if opts.DryRun {
+		return sum, nil
+	}
+	err := ns.edit(func() error {
+		for i, ref := range refs {
+			if ref.Removed && ref.Key != "" {
+				edits[i].Value = ref.New
+			}
+		}
+		var kept []*Section
+		for _, s := range ns.sections {
+			if !inAnySubtree(s.Path, removed) {
+				kept = append(kept, s)
+			}
+		}
+		ns.sections = kept
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sum, nil
+}
+
+// serverReferences returns the references to the server in the enterprise,
+// and the attributes of the attribute references, nil for section ones.
+func serverReferences(sections []*Section, enterprise, server string) ([]Reference, []*Attr) {
+	ent, srv := enterprisePath(enterprise), serverPath(enterprise, server)
+	var (
+		refs  []Reference
+		attrs []*Attr
+		roots []string // sections of the server assignments
+	)
+	for _, s := range sections {
+		if !inSubtree(s.Path, ent) || inSubtree(s.Path, srv) || inAnySubtree(s.Path, roots) {
+			continue
+		}
+		if root, ok := serverInPath(s.Path, ent, server); ok && !inSubtree(srv, root) {
+			roots = append(roots, root)
+			refs = append(refs, Reference{Path: root})
+			attrs = append(attrs, nil)
+			continue
+		}
+		for _, a := range s.Attrs {
+			if hasWord(a.Value, server) {
+				refs = append(refs, Reference{Path: s.Path, Key: a.Key, Value: a.Value})
+				attrs = append(attrs, a)
+			}
+		}
+	}
+	return refs, attrs
+}
+
+// inAnySubtree returns true if p is in the subtree of any of roots.
+func inAnySubtree(p string, roots []string) bool {
+	for _, root := range roots {
+		if inSubtree(p, root) {
+			return true
+		}
+	}
+	return false
+}
+
+// serverInPath returns the path up to the element of p, below the
+// enterprise path ent, that is the server name following the servers
+// directory, i.e. "component groups/CallCenter/servers/app01".  Elements
+// where the server name is not expected, i.e. the enterprise name or the
+// parameter alias, are never matched.
+func serverInPath(p, ent, name string) (string, bool) {
+	root, prev := ent, ""
+	for _, elem := range splitPath(p)[len(splitPath(ent)):] {
+		root = joinPath(root, elem)
+		if prev == dirServers && elem == name {
+			return root, true
+		}
+		prev = elem
+	}
+	return "", false
+}
+
+// isWordRune returns true if r may be the part of the server or host name.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-'
+}
+
+// hasWord returns true if s contains the word w, delimited by the runes
+// that are not the part of the name, i.e. "app01" in "app01.example.com".
+func hasWord(s, w string) bool {
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool { return !isWordRune(r) }) {
+		if f == w {
+			return true
+		}
+	}
+	return false
+}
+
+// listSeparators are the separators of the list values, in the order of
+// preference.
+var listSeparators = []string{";", ","}
+
+// withoutName returns the list value v without the items that mention
+// name, ok is false if v is not a list or every item mentions name.  The
+// quoted value stays quoted.
+func withoutName(v, name string) (string, bool) {
+	quoted := strings.HasPrefix(v, `"`)
+	if quoted {
+		uv, err := unquote(v)
+		if err != nil {
+			return "", false
+		}
+		v = uv
+	}
+	for _, sep := range listSeparators {
+		items := strings.Split(v, sep)
+		if len(items) < 2 {
+			continue
+		}
+		var kept []string
+		var left bool // items left, that are not empty
+		for _, item := range items {
+			if hasWord(item, name) {
+				continue
+			}
+			kept = append(kept, item)
+			left = left || strings.TrimSpace(item) != ""
+		}
+		if !left {
+			return "", false
+		}
+		nv := strings.Join(kept, sep)
+		if quoted {
+			nv = quote(nv)
+		}
+		return nv, true
+	}
+	return "", false
+}
@@ -0,0 +1,279 @@
+package siebns
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNSFile_CloneServer(t *testing.T) {
+	const servers = "/enterprises/SBA_84/servers"
+	app01 := []string{
+		servers + "/app01",
+		servers + "/app01/parameters",
+		servers + "/app01/parameters/MaxTasks",
+		servers + "/app01/parameters/Host",
+		servers + "/app01/components",
+		servers + "/app01/components/SCCObjMgr_enu",
+		servers + "/app01/components/SCCObjMgr_enu/parameters",
+		servers + "/app01/components/SCCObjMgr_enu/parameters/MaxTasks",
+		servers + "/app01/component groups",
+		servers + "/app01/component groups/CallCenter",
+	}
+	app02 := []string{
+		servers + "/app02",
+		servers + "/app02/parameters",
+		servers + "/app02/parameters/Lang",
+	}
+	clone := func(name string) []string {
+		var paths []string
+		for _, p := range app01 {
+			paths = append(paths, servers+"/"+name+p[len(servers+"/app01"):])
+		}
+		return paths
+	}
+	concat := func(paths ...[]string) []string {
+		var all []string
+		for _, p := range paths {
+			all = append(all, p...)
+		}
+		return all
+	}
+	values := map[string]string{
+		"app01":             "appXX", // must not break the longer value
+		"app01.example.com": "app03.example.com",
+	}
+	tests := []struct {
+		name      string
+		from, to  string
+		opts      CloneOptions
+		wantPaths []string
+		wantHost  string
+		wantErr   error
+	}{
+		{"new server", "app01", "app03", CloneOptions{Values: values},
+			concat(app01, app02, clone("app03")), `"app03.example.com"`, nil},
+		{"no mapping", "app01", "app03", CloneOptions{},
+			concat(app01, app02, clone("app03")), `"app01.example.com"`, nil},
+		{"existing server", "app01", "app02", CloneOptions{Values: values},
+			concat(app01, app02), "", errSectionExists},
+		{"overwrite", "app01", "app02", CloneOptions{Values: values, Overwrite: true},
+			concat(app01, clone("app02")), `"app03.example.com"`, nil},
+		{"overwrite itself", "app01", "app01", CloneOptions{Overwrite: true},
+			concat(app01, app02), "", errSectionExists},
+		{"overwrite from missing", "app05", "app02", CloneOptions{Overwrite: true},
+			concat(app01, app02), "", errSectionNotFound},
+		{"missing server", "app05", "app03", CloneOptions{},
+			concat(app01, app02), "", errSectionNotFound},
+		{"invalid name", "app01", "app/03", CloneOptions{},
+			concat(app01, app02), "", errInvalidName},
+		{"empty mapping", "app01", "app03", CloneOptions{Values: map[string]string{"": "x"}},
+			concat(app01, app02), "", errEmptyMapping},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := parseTestNSFile(t, withValidSize(testfileEnterprise))
+			defer CloseTestNSFile(ns)
+			ns.backups = NoBackup
+
+			err := ns.CloneServer("SBA_84", tt.from, tt.to, tt.opts)
+			if sectionErr(err) != tt.wantErr {
+				t.Fatalf("CloneServer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got []string
+			for _, p := range sectionPaths(ns.sections) {
+				if inSubtree(p, servers) && p != servers {
+					got = append(got, p)
+				}
+			}
+			if diff := cmp.Diff(tt.wantPaths, got); diff != "" {
+				t.Errorf("paths mismatch, (-want,+got):\n%s", diff)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			host, _ := ns.Section(servers + "/" + tt.to + "/parameters/Host").Get("Value")
+			if host != tt.wantHost {
+				t.Errorf("Host = %s, want %s", host, tt.wantHost)
+			}
+			if orig, _ := ns.Section(servers + "/app01/parameters/Host").Get("Value"); tt.to != "app01" && orig != `"app01.example.com"` {
+				t.Errorf("source Host = %s, want unchanged", orig)
+			}
+			if !ns.IsHeaderCorrect() {
+				t.Error("IsHeaderCorrect() = false after CloneServer")
+			}
+		})
+	}
+}
+
+// testfileReferences is testfileEnterprise with the references to the
+// server app01 outside of its subtree.
+const testfileReferences = testfileEnterprise + `[/enterprises/SBA_84/parameters/VirtualServers]
+	Persistence=full
+	Type=string
+	Value="1:app01:2321;2:app02:2321;"
+[/enterprises/SBA_84/component groups/CallCenter/servers]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/component groups/CallCenter/servers/app01]
+	Persistence=full
+	Type=boolean
+	Value=True
+`
+
+func TestNSFile_RemoveServer(t *testing.T) {
+	const (
+		ent     = "/enterprises/SBA_84"
+		dsconn  = ent + "/named subsystems/GatewayDataSrc/parameters/DSConnectString"
+		virtual = ent + "/parameters/VirtualServers"
+		group   = ent + "/component groups/CallCenter/servers/app01"
+	)
+	app01 := []string{
+		ent + "/servers/app01",
+		ent + "/servers/app01/parameters",
+		ent + "/servers/app01/parameters/MaxTasks",
+		ent + "/servers/app01/parameters/Host",
+		ent + "/servers/app01/components",
+		ent + "/servers/app01/components/SCCObjMgr_enu",
+		ent + "/servers/app01/components/SCCObjMgr_enu/parameters",
+		ent + "/servers/app01/components/SCCObjMgr_enu/parameters/MaxTasks",
+		ent + "/servers/app01/component groups",
+		ent + "/servers/app01/component groups/CallCenter",
+	}
+	found := []Reference{
+		{Path: dsconn, Key: "Value", Value: `"app01.example.com:2320"`},
+		{Path: virtual, Key: "Value", Value: `"1:app01:2321;2:app02:2321;"`},
+		{Path: group},
+	}
+	removed := []Reference{
+		found[0], // not a list
+		{Path: virtual, Key: "Value", Value: `"1:app01:2321;2:app02:2321;"`, Removed: true, New: `"2:app02:2321;"`},
+		{Path: group, Removed: true},
+	}
+	tests := []struct {
+		name        string
+		server      string
+		opts        RemoveOptions
+		want        *RemoveSummary
+		wantRemoved []string // sections removed from the file
+		wantVirtual string
+		wantErr     error
+	}{
+		{"dry run", "app01", RemoveOptions{RemoveReferences: true, DryRun: true},
+			&RemoveSummary{Sections: app01, References: removed},
+			nil, `"1:app01:2321;2:app02:2321;"`, nil},
+		{"report references", "app01", RemoveOptions{},
+			&RemoveSummary{Sections: app01, References: found},
+			app01, `"1:app01:2321;2:app02:2321;"`, nil},
+		{"remove references", "app01", RemoveOptions{RemoveReferences: true},
+			&RemoveSummary{Sections: app01, References: removed},
+			append(app01[:len(app01):len(app01)], group), `"2:app02:2321;"`, nil},
+		{"missing server", "app05", RemoveOptions{},
+			nil, nil, `"1:app01:2321;2:app02:2321;"`, errSectionNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := parseTestNSFile(t, withValidSize(testfileReferences))
+			defer CloseTestNSFile(ns)
+			ns.backups = NoBackup
+			before := sectionPaths(ns.sections)
+
+			got, err := ns.RemoveServer("SBA_84", tt.server, tt.opts)
+			if sectionErr(err) != tt.wantErr {
+				t.Fatalf("RemoveServer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("RemoveServer() mismatch, (-want,+got):\n%s", diff)
+			}
+			var gone []string
+			for _, p := range before {
+				if ns.Section(p) == nil {
+					gone = append(gone, p)
+				}
+			}
+			if diff := cmp.Diff(tt.wantRemoved, gone); diff != "" {
+				t.Errorf("removed sections mismatch, (-want,+got):\n%s", diff)
+			}
+			if v, _ := ns.Section(virtual).Get("Value"); v != tt.wantVirtual {
+				t.Errorf("VirtualServers = %s, want %s", v, tt.wantVirtual)
+			}
+			if tt.wantErr == nil && !tt.opts.DryRun && !ns.IsHeaderCorrect() {
+				t.Error("IsHeaderCorrect() = false after RemoveServer")
+			}
+		})
+	}
+}
+
+func TestNSFile_RemoveServer_nameClash(t *testing.T) {
+	// the enterprise, the server and the component group share the name.
+	contents := strings.NewReplacer("SBA_84", "siebel", "app01", "siebel", "CallCenter", "siebel").Replace(testfileReferences)
+	ns := parseTestNSFile(t, withValidSize(contents))
+	defer CloseTestNSFile(ns)
+	ns.backups = NoBackup
+
+	const (
+		ent   = "/enterprises/siebel"
+		group = ent + "/component groups/siebel"
+	)
+	sum, err := ns.RemoveServer("siebel", "siebel", RemoveOptions{RemoveReferences: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var removed []string
+	for _, ref := range sum.References {
+		if ref.Key == "" {
+			removed = append(removed, ref.Path)
+		}
+	}
+	if diff := cmp.Diff([]string{group + "/servers/siebel"}, removed); diff != "" {
+		t.Errorf("removed references mismatch, (-want,+got):\n%s", diff)
+	}
+	for _, p := range []string{ent, ent + "/parameters/MaxTasks", group, group + "/servers", ent + "/servers/app02"} {
+		if ns.Section(p) == nil {
+			t.Errorf("section %s was removed", p)
+		}
+	}
+	for _, p := range []string{ent + "/servers/siebel", group + "/servers/siebel"} {
+		if ns.Section(p) != nil {
+			t.Errorf("section %s was not removed", p)
+		}
+	}
+}
+
+func TestReference_String(t *testing.T) {
+	tests := []struct {
+		ref  Reference
+		want string
+	}{
+		{Reference{Path: "/a/app01"}, "[/a/app01]"},
+		{Reference{Path: "/a/app01", Removed: true}, "[/a/app01] (removed)"},
+		{Reference{Path: "/a", Key: "Value", Value: "app01;app02"}, "[/a] Value=app01;app02"},
+		{Reference{Path: "/a", Key: "Value", Value: "app01;app02", Removed: true, New: "app02"}, "[/a] Value=app01;app02 -> app02"},
+	}
+	for _, tt := range tests {
+		if got := tt.ref.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func Test_withoutName(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   string
+		wantOk bool
+	}{
+		{`"1:app01:2321;2:app02:2321;"`, `"2:app02:2321;"`, true},
+		{"app02,app01,app03", "app02,app03", true},
+		{"app01.example.com", "", false},
+		{"app01;", "", false},
+		{`"unbalanced;app01`, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := withoutName(tt.value, "app01")
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("withoutName(%s) = %q, %v, want %q, %v", tt.value, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
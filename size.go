@@ -13,11 +13,18 @@ func decodeSize(b []byte) (int64, binary.ByteOrder, error) {
 	if err != nil {
 		return 0, nil, err
 	}
+	if len(base64Size) != 8 {
+		return 0, nil, errChecksumCorrupt
+	}
 
-	size := sizeFromBinary(base64Size, binary.LittleEndian)
-	if size > int64(math.MaxInt32<<1) {
+	size, err := sizeFromBinary(base64Size, binary.LittleEndian)
+	if err != nil {
+		return 0, nil, err
+	}
+	if size < 0 || size > int64(math.MaxInt32<<1) {
 		// assuming that size of the siebNS file can't be more than 4TB...
-		return sizeFromBinary(base64Size, binary.BigEndian), binary.BigEndian, nil
+		size, err = sizeFromBinary(base64Size, binary.BigEndian)
+		return size, binary.BigEndian, err
 	}
 	return size, binary.LittleEndian, nil
 }
@@ -35,14 +42,13 @@ func encodeSize(size int64, byteOder binary.ByteOrder) ([]byte, error) {
 }
 
 // sizeFromBinary translates the byte sequence into the int64.  It is a
-// wrapper around the binary.Read()
-func sizeFromBinary(b []byte, byteOrder binary.ByteOrder) (size int64) {
+// wrapper around the binary.Read(), the error is returned if b is shorter
+// than 8 bytes.
+func sizeFromBinary(b []byte, byteOrder binary.ByteOrder) (size int64, err error) {
 	if byteOrder == nil {
 		panic(errByteOrderNil)
 	}
-	if err := binary.Read(bytes.NewReader(b), byteOrder, &size); err != nil {
-		panic(err)
-	}
+	err = binary.Read(bytes.NewReader(b), byteOrder, &size)
 	return
 }
 
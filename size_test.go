@@ -15,6 +15,10 @@ func Test_decodeSize(t *testing.T) {
 	b64big01020304 := make([]byte, coding.EncodedLen(len(big01020304)))
 	coding.Encode(b64big01020304, big01020304)
 
+	big0180 := []byte{0, 0, 0, 0, 0, 0, 0x01, 0x80}
+	b64big0180 := make([]byte, coding.EncodedLen(len(big0180)))
+	coding.Encode(b64big0180, big0180)
+
 	type args struct {
 		b []byte
 	}
@@ -27,7 +31,10 @@ func Test_decodeSize(t *testing.T) {
 	}{
 		{"little endian", args{b64little01020304}, 0x0000000001020304, binary.LittleEndian, false},
 		{"big endian", args{b64big01020304}, 0x0000000001020304, binary.BigEndian, false},
+		{"big endian, negative if little", args{b64big0180}, 0x0180, binary.BigEndian, false},
 		{"invalid b64", args{big01020304}, 0, nil, true},
+		{"short", args{[]byte("AAAA")}, 0, nil, true},
+		{"short padded", args{[]byte("DAMAAAAAAA==             ")}, 0, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -92,20 +99,22 @@ func Test_sizeFromBinary(t *testing.T) {
 		name      string
 		args      args
 		wantSize  int64
+		wantErr   bool
 		wantPanic bool
 	}{
 		{"little endian",
 			args{[]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07},
 				binary.LittleEndian},
 			0x0706050403020100,
-			false},
+			false, false},
 		{"big endian",
 			args{[]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07},
 				binary.BigEndian},
 			0x0001020304050607,
-			false},
-		{"byteorder nil", args{[]byte{}, nil}, 0, true},
-		{"bytes nil", args{nil, binary.BigEndian}, 0, true},
+			false, false},
+		{"byteorder nil", args{[]byte{}, nil}, 0, false, true},
+		{"bytes nil", args{nil, binary.BigEndian}, 0, true, false},
+		{"short", args{[]byte{0x00, 0x01, 0x02}, binary.LittleEndian}, 0, true, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -118,7 +127,11 @@ func Test_sizeFromBinary(t *testing.T) {
 					}
 				}
 			}()
-			if gotSize := sizeFromBinary(tt.args.b, tt.args.endianness); gotSize != tt.wantSize {
+			gotSize, err := sizeFromBinary(tt.args.b, tt.args.endianness)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("sizeFromBinary() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotSize != tt.wantSize {
 				t.Errorf("sizeFromBinary() = %v, want %v", gotSize, tt.wantSize)
 			}
 		})
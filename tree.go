@@ -0,0 +1,183 @@
+package siebns
+
+import (
+	"path"
+	"strings"
+)
+
+// Node is the node of the section tree.  Section paths form a tree, i.e.
+// "/enterprises/SBA_84/servers" is a child of "/enterprises/SBA_84".  Each
+// element of the path is a node, but not every node has a section: if the
+// file is missing the section for some intermediate path, the node is still
+// present in the tree and its Section is nil.
+type Node struct {
+	name     string
+	path     string
+	section  *Section
+	parent   *Node
+	children []*Node
+}
+
+// Tree builds the tree of the sections and returns its root node "/".  The
+// tree is a snapshot: it is not updated when the sections change.
+func (ns *NSFile) Tree() *Node {
+	return buildTree(ns.sections)
+}
+
+// Glob returns the nodes with the paths matching the pattern.  See
+// Node.Glob for the pattern syntax.
+func (ns *NSFile) Glob(pattern string) ([]*Node, error) {
+	return ns.Tree().Glob(pattern)
+}
+
+// buildTree returns the root node of the tree built from sections.  The
+// children are ordered as their sections appear in the file.
+func buildTree(sections []*Section) *Node {
+	root := &Node{name: "/", path: "/"}
+	for _, s := range sections {
+		n := root
+		for _, name := range splitPath(s.Path) {
+			n = n.child(name, true)
+		}
+		if n.section == nil {
+			n.section = s
+		}
+	}
+	return root
+}
+
+// splitPath returns the elements of the section path.  The root path "/"
+// has no elements.
+func splitPath(p string) []string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+// joinPath returns the section path of the parent path and the name.
+func joinPath(parent, name string) string {
+	return strings.TrimSuffix(parent, "/") + "/" + name
+}
+
+// child returns the child node with the name.  If there's no such child and
+// create is true, the child is added, otherwise nil is returned.
+func (n *Node) child(name string, create bool) *Node {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	if !create {
+		return nil
+	}
+	c := &Node{name: name, path: joinPath(n.path, name), parent: n}
+	n.children = append(n.children, c)
+	return c
+}
+
+// Name returns the last element of the node path, i.e. "MaxTasks" for
+// "/enterprises/SBA_84/parameters/MaxTasks".
+func (n *Node) Name() string {
+	return n.name
+}
+
+// Path returns the full path of the node.
+func (n *Node) Path() string {
+	return n.path
+}
+
+// Section returns the section of the node, or nil if the file has no
+// section for this path.
+func (n *Node) Section() *Section {
+	return n.section
+}
+
+// Parent returns the parent node, or nil for the root node.
+func (n *Node) Parent() *Node {
+	return n.parent
+}
+
+// Children returns the child nodes.
+func (n *Node) Children() []*Node {
+	return n.children
+}
+
+// root returns the root node of the tree.
+func (n *Node) root() *Node {
+	for n.parent != nil {
+		n = n.parent
+	}
+	return n
+}
+
+// Get returns the node with the path, or nil if there's no such node.
+// Absolute paths are looked up from the root of the tree, relative ones
+// from n.
+func (n *Node) Get(p string) *Node {
+	if strings.HasPrefix(p, "/") {
+		n = n.root()
+	}
+	for _, name := range splitPath(p) {
+		if n = n.child(name, false); n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// Walk calls fn for the node and all its descendants, parents before
+// children.  If fn returns an error, walking stops and the error is
+// returned.
+func (n *Node) Walk(fn func(*Node) error) error {
+	if err := fn(n); err != nil {
+		return err
+	}
+	for _, c := range n.children {
+		if err := c.Walk(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Glob returns the nodes with the paths matching the pattern, in the tree
+// order.  The pattern syntax is the one of path.Match, applied to each path
+// element, so that "/enterprises/*/servers/*/parameters/MaxTasks" matches
+// the MaxTasks parameter of every server.  Absolute patterns are matched
+// from the root of the tree, relative ones from n.  The only possible error
+// is path.ErrBadPattern.
+func (n *Node) Glob(pattern string) ([]*Node, error) {
+	if strings.HasPrefix(pattern, "/") {
+		n = n.root()
+	}
+	// validating the whole pattern, as path.Match may not report the
+	// malformed pattern if it does not match the name.
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	return n.glob(splitPath(pattern))
+}
+
+func (n *Node) glob(elems []string) ([]*Node, error) {
+	if len(elems) == 0 {
+		return []*Node{n}, nil
+	}
+	var found []*Node
+	for _, c := range n.children {
+		ok, err := path.Match(elems[0], c.name)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		matches, err := c.glob(elems[1:])
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, matches...)
+	}
+	return found, nil
+}
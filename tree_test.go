@@ -0,0 +1,177 @@
+package siebns
+
+import (
+	"errors"
+	"path"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testfileServers = testfileSample + `[/enterprises/SBA_84/servers]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/servers/app01]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/servers/app01/parameters/MaxTasks]
+	Persistence=full
+	Type=integer
+	Value=200
+[/enterprises/SBA_84/servers/app02]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/servers/app02/parameters]
+	Persistence=full
+	Type=empty
+[/enterprises/SBA_84/servers/app02/parameters/MaxTasks]
+	Persistence=full
+	Type=integer
+	Value=100
+`
+
+// nodePaths returns the paths of the nodes.
+func nodePaths(nodes []*Node) []string {
+	var paths []string
+	for _, n := range nodes {
+		paths = append(paths, n.Path())
+	}
+	return paths
+}
+
+func TestNode_Get(t *testing.T) {
+	ns := parseTestNSFile(t, testfileServers)
+	defer CloseTestNSFile(ns)
+	root := ns.Tree()
+
+	tests := []struct {
+		name        string
+		from        *Node
+		path        string
+		wantPath    string
+		wantSection bool
+	}{
+		{"root", root, "/", "/", true},
+		{"absolute", root, "/enterprises/SBA_84/servers/app01", "/enterprises/SBA_84/servers/app01", true},
+		{"trailing slash", root, "/enterprises/", "/enterprises", true},
+		{"no section", root, "/enterprises/SBA_84/servers/app01/parameters", "/enterprises/SBA_84/servers/app01/parameters", false},
+		{"relative", root.Get("/enterprises/SBA_84"), "servers/app02", "/enterprises/SBA_84/servers/app02", true},
+		{"absolute from child", root.Get("/enterprises/SBA_84/servers"), "/enterprises", "/enterprises", true},
+		{"not found", root, "/enterprises/SBA_85", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.from.Get(tt.path)
+			if tt.wantPath == "" {
+				if got != nil {
+					t.Errorf("Get() = %s, want nil", got.Path())
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("Get() = nil")
+			}
+			if got.Path() != tt.wantPath {
+				t.Errorf("Get().Path() = %q, want %q", got.Path(), tt.wantPath)
+			}
+			if (got.Section() != nil) != tt.wantSection {
+				t.Errorf("Get().Section() = %v, wantSection %v", got.Section(), tt.wantSection)
+			}
+			if got.Section() != nil && got.Section().Path != got.Path() {
+				t.Errorf("Section().Path = %q, want %q", got.Section().Path, got.Path())
+			}
+		})
+	}
+}
+
+func TestNode_ParentChildren(t *testing.T) {
+	ns := parseTestNSFile(t, testfileServers)
+	defer CloseTestNSFile(ns)
+	root := ns.Tree()
+
+	if root.Parent() != nil {
+		t.Error("root has parent")
+	}
+	servers := root.Get("/enterprises/SBA_84/servers")
+	if diff := cmp.Diff([]string{"/enterprises/SBA_84/servers/app01", "/enterprises/SBA_84/servers/app02"}, nodePaths(servers.Children())); diff != "" {
+		t.Errorf("Children() mismatch, (-want,+got):\n%s", diff)
+	}
+	app01 := servers.Children()[0]
+	if app01.Name() != "app01" {
+		t.Errorf("Name() = %q, want %q", app01.Name(), "app01")
+	}
+	if app01.Parent() != servers {
+		t.Errorf("Parent() = %v, want %v", app01.Parent().Path(), servers.Path())
+	}
+}
+
+func TestNode_Walk(t *testing.T) {
+	ns := parseTestNSFile(t, testfileServers)
+	defer CloseTestNSFile(ns)
+	root := ns.Tree()
+
+	var got []string
+	err := root.Get("/enterprises/SBA_84/servers").Walk(func(n *Node) error {
+		got = append(got, n.Name())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"servers", "app01", "parameters", "MaxTasks", "app02", "parameters", "MaxTasks"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Walk() mismatch, (-want,+got):\n%s", diff)
+	}
+
+	errStop := errors.New("stop")
+	var visited int
+	err = root.Walk(func(n *Node) error {
+		visited++
+		if n.Name() == "SBA_84" {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop {
+		t.Errorf("Walk() error = %v, want %v", err, errStop)
+	}
+	if visited != 3 {
+		t.Errorf("visited = %d, want 3", visited)
+	}
+}
+
+func TestNode_Glob(t *testing.T) {
+	ns := parseTestNSFile(t, testfileServers)
+	defer CloseTestNSFile(ns)
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    []string
+		wantErr error
+	}{
+		{"every server",
+			"/enterprises/*/servers/*/parameters/MaxTasks",
+			[]string{"/enterprises/SBA_84/servers/app01/parameters/MaxTasks", "/enterprises/SBA_84/servers/app02/parameters/MaxTasks"},
+			nil},
+		{"character class",
+			"/enterprises/SBA_8[0-4]/servers/app0?",
+			[]string{"/enterprises/SBA_84/servers/app01", "/enterprises/SBA_84/servers/app02"},
+			nil},
+		{"star does not cross slash", "/enterprises/*/MaxTasks", nil, nil},
+		{"relative to root", "enterprises", []string{"/enterprises"}, nil},
+		{"bad pattern", "/enterprises/[", nil, path.ErrBadPattern},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ns.Glob(tt.pattern)
+			if err != tt.wantErr {
+				t.Errorf("Glob() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, nodePaths(got)); diff != "" {
+				t.Errorf("Glob() mismatch, (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package siebns
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Severity is the severity of the validation finding.
+type Severity int
+
+// Severities of the findings.
+const (
+	// SeverityWarning is the issue that Siebel tolerates, but that is
+	// likely to be the result of the mistake.
+	SeverityWarning Severity = iota
+	// SeverityError is the issue that makes the file invalid.
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "Severity(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// Finding is the issue found by Validate.
+type Finding struct {
+	Line     int    // line number in the file, 0 if not related to the line
+	Path     string // section path, if related to the section
+	Severity Severity
+	Message  string
+}
+
+func (f Finding) String() string {
+	var sb strings.Builder
+	if f.Line > 0 {
+		fmt.Fprintf(&sb, "line %d: ", f.Line)
+	}
+	sb.WriteString(f.Severity.String())
+	sb.WriteString(": ")
+	if f.Path != "" {
+		sb.WriteString("[" + f.Path + "] ")
+	}
+	sb.WriteString(f.Message)
+	return sb.String()
+}
+
+// HasErrors returns true if there are findings of the error severity.
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity >= SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the whole file and returns the findings ordered by the
+// line number.  The file is valid if there are no findings of the error
+// severity.
+func (ns *NSFile) Validate() []Finding {
+	if ns.header == nil {
+		return []Finding{{Severity: SeverityError, Message: errNotInitialised.Error()}}
+	}
+	var v validator
+	v.checkHeader(ns.header)
+	v.checkSize(ns)
+	v.checkMalformed(ns.malformed)
+	v.checkPreamble(&ns.body)
+	v.checkSections(ns.sections)
+	v.checkLineEndings(ns.header, ns.sections)
+
+	sort.SliceStable(v.findings, func(i, j int) bool {
+		return v.findings[i].Line < v.findings[j].Line
+	})
+	return v.findings
+}
+
+// validator collects the findings.
+type validator struct {
+	findings []Finding
+}
+
+func (v *validator) add(line int, path string, sev Severity, format string, a ...interface{}) {
+	v.findings = append(v.findings, Finding{
+		Line:     line,
+		Path:     path,
+		Severity: sev,
+		Message:  fmt.Sprintf(format, a...),
+	})
+}
+
+// checkHeader checks the version lines, that are the lines 2 and 3.
+func (v *validator) checkHeader(hdr *nsHeader) {
+	if _, err := parseSiebelVersion(hdr.version.siebel); err != nil {
+		v.add(2, "", SeverityWarning, "Siebel version %q: %s", hdr.version.siebel, err)
+	}
+	format, _ := ParseVersion(strings.TrimSpace(hdr.version.nsfile))
+	if err := (Header{Format: format}).CheckFormat(); err != nil {
+		v.add(3, "", SeverityError, "%s %q", err, hdr.version.nsfile)
+	}
+}
+
+func (v *validator) checkSize(ns *NSFile) {
+	size, err := ns.header.readEncodedSize(ns)
+	if err != nil {
+		v.add(0, "", SeverityError, "unable to read the encoded size: %s", err)
+		return
+	}
+	if actual := ns.Size(); size != actual {
+		v.add(0, "", SeverityError, "encoded size %d does not match the file size %d", size, actual)
+	}
+}
+
+// checkMalformed reports the lines of the body that could not be parsed.
+func (v *validator) checkMalformed(malformed []DroppedLine) {
+	for _, d := range malformed {
+		v.add(d.Line, "", SeverityError, "%s: %q", d.Err, d.Text)
+	}
+}
+
+func (v *validator) checkPreamble(b *body) {
+	for i, raw := range b.preamble {
+		if !isBlank(trimEOL([]byte(raw))) {
+			v.add(b.start+i, "", SeverityError, "%s", errAttrOutsideSection)
+		}
+	}
+}
+
+func (v *validator) checkSections(sections []*Section) {
+	seen := make(map[string]*Section, len(sections))
+	for _, s := range sections {
+		if first, dup := seen[s.Path]; dup {
+			v.add(s.line, s.Path, SeverityError, "duplicate section, first defined at line %d", first.line)
+			continue
+		}
+		seen[s.Path] = s
+	}
+	for _, s := range sections {
+		if s.Path != "/" {
+			if _, ok := seen[path.Dir(s.Path)]; !ok {
+				v.add(s.line, s.Path, SeverityError, "%s: %s", errNoParent, path.Dir(s.Path))
+			}
+		}
+		v.checkAttrs(s)
+		v.checkValue(s)
+	}
+}
+
+func (v *validator) checkAttrs(s *Section) {
+	keys := make(map[string]bool, len(s.Attrs))
+	for _, a := range s.Attrs {
+		if keys[a.Key] {
+			v.add(a.line, s.Path, SeverityError, "duplicate attribute %s", a.Key)
+		}
+		keys[a.Key] = true
+		if strings.TrimRight(a.Value, " \t") != a.Value {
+			v.add(a.line, s.Path, SeverityWarning, "trailing whitespace in %s", a.Key)
+		}
+		if strings.Contains(a.Value, `"`) {
+			if _, err := unquote(a.Value); err != nil {
+				v.add(a.line, s.Path, SeverityError, "%s: %s", a.Key, err)
+			}
+		}
+	}
+}
+
+// checkValue checks that Value matches the declared Type.
+func (v *validator) checkValue(s *Section) {
+	typ := s.attr(keyType)
+	val := s.attr(keyValue)
+	if typ == nil {
+		if val != nil {
+			v.add(val.line, s.Path, SeverityError, "%s", errNoType)
+		}
+		return
+	}
+	switch typ.Value {
+	case TypeEmpty:
+		if val != nil {
+			v.add(val.line, s.Path, SeverityWarning, "Value in the section of the empty type")
+		}
+		return
+	case TypeString, TypeInteger, TypeBoolean:
+	default:
+		v.add(typ.line, s.Path, SeverityWarning, "unknown Type %q", typ.Value)
+		return
+	}
+	if val == nil {
+		v.add(typ.line, s.Path, SeverityError, "%s", errNoValue)
+		return
+	}
+	var err error
+	switch typ.Value {
+	case TypeInteger:
+		_, err = s.Int()
+	case TypeBoolean:
+		_, err = s.Bool()
+	}
+	if err != nil {
+		v.add(val.line, s.Path, SeverityError, "%s %q is not %s", keyValue, val.Value, typ.Value)
+	}
+}
+
+// checkLineEndings reports the lines that end differently from the header.
+func (v *validator) checkLineEndings(hdr *nsHeader, sections []*Section) {
+	eol := hdr.eol()
+	var (
+		count int
+		first int
+	)
+	check := func(lineEOL string, line int) {
+		if lineEOL == "" || lineEOL == eol {
+			return
+		}
+		if count == 0 {
+			first = line
+		}
+		count++
+	}
+	for _, s := range sections {
+		check(s.eol, s.line)
+		for _, a := range s.Attrs {
+			check(a.eol, a.line)
+		}
+	}
+	if count > 0 {
+		v.add(first, "", SeverityWarning, "mixed line endings: %d lines do not end with %s as the header does", count, eolName(eol))
+	}
+}
+
+// eolName returns the name of the line ending.
+func eolName(eol string) string {
+	if eol == string(crlf) {
+		return "CRLF"
+	}
+	return "LF"
+}
@@ -0,0 +1,140 @@
+package siebns
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNSFile_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     []Finding
+	}{
+		{"valid", withValidSize(testfileSample), nil},
+		{"versions", withValidSize(strings.Replace(testfileSample, "16.0.0.0 [23057] ENU\n1.2\n", "16.0.0.0\n2.0\n", 1)), []Finding{
+			{Line: 2, Severity: SeverityWarning, Message: `Siebel version "16.0.0.0": invalid version`},
+			{Line: 3, Severity: SeverityError, Message: `unsupported nsfile format version "2.0"`},
+		}},
+		{"size mismatch", testfileSample, []Finding{
+			{Severity: SeverityError, Message: "encoded size 780 does not match the file size 394"},
+		}},
+		{"issues", withValidSize(testfileEmpty[:len(testfileEmpty)-len("[/]\n\tPersistence=partial\n\tType=empty\n")] +
+			"\tType=empty\n" + // 6
+			"[/]\n" +
+			"\tType=empty\n" +
+			"[/nope/SBA_84]\n" + // 9
+			"\tType=integer\n" +
+			"\tValue=abc\n" +
+			"[/]\n" + // 12
+			"\tType=string\n" +
+			"\tValue=\"unterminated\n" +
+			"\tDescription=trailing  \n" + // 15
+			"\tDescription=\"x\"\r\n" +
+			"[/enterprises]\r\n" +
+			"\tType=boolean\n" + // 18
+			"\tValue=maybe\n" +
+			"[/p]\n" +
+			"\tValue=1\n" + // 21
+			"[/q]\n" +
+			"\tType=empty\n" +
+			"\tValue=1\n" + // 24
+			"[/r]\n" +
+			"\tType=long\n" +
+			"[/s]\n" + // 27
+			"\tType=integer\n"),
+			[]Finding{
+				{Line: 6, Severity: SeverityError, Message: "attribute outside of any section"},
+				{Line: 9, Path: "/nope/SBA_84", Severity: SeverityError, Message: "parent section does not exist: /nope"},
+				{Line: 11, Path: "/nope/SBA_84", Severity: SeverityError, Message: `Value "abc" is not integer`},
+				{Line: 12, Path: "/", Severity: SeverityError, Message: "duplicate section, first defined at line 7"},
+				{Line: 14, Path: "/", Severity: SeverityError, Message: "Value: unbalanced quotes in the value"},
+				{Line: 15, Path: "/", Severity: SeverityWarning, Message: "trailing whitespace in Description"},
+				{Line: 16, Path: "/", Severity: SeverityError, Message: "duplicate attribute Description"},
+				{Line: 16, Severity: SeverityWarning, Message: "mixed line endings: 2 lines do not end with LF as the header does"},
+				{Line: 19, Path: "/enterprises", Severity: SeverityError, Message: `Value "maybe" is not boolean`},
+				{Line: 21, Path: "/p", Severity: SeverityError, Message: "section has no Type attribute"},
+				{Line: 24, Path: "/q", Severity: SeverityWarning, Message: "Value in the section of the empty type"},
+				{Line: 26, Path: "/r", Severity: SeverityWarning, Message: `unknown Type "long"`},
+				{Line: 28, Path: "/s", Severity: SeverityError, Message: "section has no Value attribute"},
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns, err := ParseBytes([]byte(tt.contents))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ns.Validate()
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Validate() mismatch, (-want,+got):\n%s", diff)
+			}
+			if HasErrors(got) != (tt.want != nil) {
+				t.Errorf("HasErrors() = %v", HasErrors(got))
+			}
+		})
+	}
+}
+
+func TestFinding_String(t *testing.T) {
+	tests := []struct {
+		name string
+		f    Finding
+		want string
+	}{
+		{"full", Finding{Line: 12, Path: "/", Severity: SeverityError, Message: "boom"}, "line 12: error: [/] boom"},
+		{"no line", Finding{Severity: SeverityWarning, Message: "hmm"}, "warning: hmm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.f.String(); got != tt.want {
+				t.Errorf("Finding.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNSFile_MalformedBody(t *testing.T) {
+	malformed := strings.Replace(testfileSample, "[/enterprises]\n\tPersistence=full\n\tType=empty", "[/enterprises]\n\tPersistence=full\nType=empty", 1)
+	ns := parseTestNSFile(t, malformed)
+	defer CloseTestNSFile(ns)
+	ns.backups = NoBackup
+
+	if err := ns.BodyErr(); err == nil {
+		t.Fatal("BodyErr() = nil, want the malformed line error")
+	}
+	want := []Finding{
+		{Severity: SeverityError, Message: "encoded size 780 does not match the file size 393"},
+		{Line: 11, Severity: SeverityError, Message: `malformed line, expected section header or attribute: "Type=empty"`},
+	}
+	if diff := cmp.Diff(want, ns.Validate()); diff != "" {
+		t.Errorf("Validate() mismatch, (-want,+got):\n%s", diff)
+	}
+	if s := ns.Section("/enterprises/SBA_84/parameters/MaxTasks"); s == nil {
+		t.Error("well-formed sections are not kept")
+	}
+	// the malformed lines would be lost, if the file was written.
+	if err := ns.Save(); err != ns.BodyErr() {
+		t.Errorf("Save() error = %v, want %v", err, ns.BodyErr())
+	}
+	if err := ns.SetParam("/enterprises", "Type", "empty"); err != ns.BodyErr() {
+		t.Errorf("SetParam() error = %v, want %v", err, ns.BodyErr())
+	}
+	// the size is fixed in place.
+	if _, err := ns.FixSize(); err != nil {
+		t.Fatal(err)
+	}
+	if !ns.IsHeaderCorrect() {
+		t.Error("IsHeaderCorrect() = false after FixSize")
+	}
+	got, err := ioutil.ReadFile(ns.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(withValidSize(malformed), string(got)); diff != "" {
+		t.Errorf("file mismatch, (-want,+got):\n%s", diff)
+	}
+}
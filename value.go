@@ -0,0 +1,232 @@
+package siebns
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parameter types, as declared by the Type attribute of the section.
+const (
+	TypeEmpty   = "empty"
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeBoolean = "boolean"
+)
+
+// Attribute keys of the section.
+const (
+	keyPersistence = "Persistence"
+	keyType        = "Type"
+	keyValue       = "Value"
+)
+
+// boolean values as they are written to the file.
+const (
+	valTrue  = "True"
+	valFalse = "False"
+)
+
+// sectionError is an error related to the specific section.
+type sectionError struct {
+	path string
+	err  error
+}
+
+func (e *sectionError) Error() string {
+	return e.path + ": " + e.err.Error()
+}
+
+// Type returns the declared type of the section value, one of the Type*
+// constants, or an empty string if the section has no Type attribute.
+func (s *Section) Type() string {
+	typ, _ := s.Get(keyType)
+	return typ
+}
+
+// Set sets the value of the attribute key, appending the attribute to the
+// section if it does not exist.
+func (s *Section) Set(key, value string) {
+	if a := s.attr(key); a != nil {
+		a.Value = value
+		return
+	}
+	s.Attrs = append(s.Attrs, &Attr{Key: key, Value: value})
+}
+
+// value returns the raw value of the section if it is of the type typ.
+func (s *Section) value(typ string) (string, error) {
+	if err := s.checkType(typ); err != nil {
+		return "", err
+	}
+	v, ok := s.Get(keyValue)
+	if !ok {
+		return "", &sectionError{s.Path, errNoValue}
+	}
+	return v, nil
+}
+
+// checkType returns an error if the declared type of the section is not
+// typ.
+func (s *Section) checkType(typ string) error {
+	switch s.Type() {
+	case typ:
+		return nil
+	case "":
+		return &sectionError{s.Path, errNoType}
+	default:
+		return &sectionError{s.Path, errTypeMismatch}
+	}
+}
+
+// String returns the unquoted value of the section of the string type.
+func (s *Section) String() (string, error) {
+	v, err := s.value(TypeString)
+	if err != nil {
+		return "", err
+	}
+	str, err := unquote(v)
+	if err != nil {
+		return "", &sectionError{s.Path, err}
+	}
+	return str, nil
+}
+
+// Int returns the value of the section of the integer type.
+func (s *Section) Int() (int64, error) {
+	v, err := s.value(TypeInteger)
+	if err != nil {
+		return 0, err
+	}
+	i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	if err != nil {
+		return 0, &sectionError{s.Path, errInvalidValue}
+	}
+	return i, nil
+}
+
+// Bool returns the value of the section of the boolean type.  Siebel
+// writes booleans as True and False, but other spellings accepted by
+// strconv.ParseBool, and Y/N, are recognised as well.
+func (s *Section) Bool() (bool, error) {
+	v, err := s.value(TypeBoolean)
+	if err != nil {
+		return false, err
+	}
+	b, ok := parseBool(v)
+	if !ok {
+		return false, &sectionError{s.Path, errInvalidValue}
+	}
+	return b, nil
+}
+
+// Duration returns the value of the section of the integer type as the
+// duration, unit is the unit of the parameter, i.e. time.Second for
+// parameters measured in seconds.
+func (s *Section) Duration(unit time.Duration) (time.Duration, error) {
+	i, err := s.Int()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(i) * unit, nil
+}
+
+// SetString sets the value of the section of the string type, quoting it.
+func (s *Section) SetString(v string) error {
+	if err := s.checkType(TypeString); err != nil {
+		return err
+	}
+	s.Set(keyValue, quote(v))
+	return nil
+}
+
+// SetInt sets the value of the section of the integer type.
+func (s *Section) SetInt(v int64) error {
+	if err := s.checkType(TypeInteger); err != nil {
+		return err
+	}
+	s.Set(keyValue, strconv.FormatInt(v, 10))
+	return nil
+}
+
+// SetBool sets the value of the section of the boolean type.
+func (s *Section) SetBool(v bool) error {
+	if err := s.checkType(TypeBoolean); err != nil {
+		return err
+	}
+	s.Set(keyValue, formatBool(v))
+	return nil
+}
+
+// SetDuration sets the value of the section of the integer type to the
+// duration d expressed in units.  d must be a multiple of the unit.
+func (s *Section) SetDuration(d time.Duration, unit time.Duration) error {
+	if unit <= 0 || d%unit != 0 {
+		return &sectionError{s.Path, errInvalidValue}
+	}
+	return s.SetInt(int64(d / unit))
+}
+
+func parseBool(v string) (bool, bool) {
+	switch strings.ToUpper(strings.TrimSpace(v)) {
+	case "Y":
+		return true, true
+	case "N":
+		return false, true
+	}
+	b, err := strconv.ParseBool(strings.ToLower(strings.TrimSpace(v)))
+	return b, err == nil
+}
+
+func formatBool(v bool) string {
+	if v {
+		return valTrue
+	}
+	return valFalse
+}
+
+// quote returns s in double quotes, with double quotes escaped with a
+// backslash.  Backslashes are not escaped, as Siebel writes Windows paths,
+// i.e. "\\srv\fs", as they are.
+func quote(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s) + 2)
+	sb.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(s[i])
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// unquote reverses quote.  Values that are not quoted are returned as is.
+// The backslash followed by the double quote, that is not the closing one,
+// is the escaped double quote, any other backslash is kept as is, so that
+// the path ending with the backslash, i.e. "C:\siebel\", survives.
+func unquote(v string) (string, error) {
+	if !strings.HasPrefix(v, `"`) {
+		if strings.Contains(v, `"`) {
+			return "", errUnbalancedQuotes
+		}
+		return v, nil
+	}
+	var sb strings.Builder
+	for i := 1; i < len(v); i++ {
+		switch v[i] {
+		case '\\':
+			if i+2 < len(v) && v[i+1] == '"' {
+				i++
+			}
+		case '"':
+			if i != len(v)-1 {
+				return "", errUnbalancedQuotes
+			}
+			return sb.String(), nil
+		}
+		sb.WriteByte(v[i])
+	}
+	return "", errUnbalancedQuotes
+}
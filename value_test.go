@@ -0,0 +1,191 @@
+package siebns
+
+import (
+	"testing"
+	"time"
+)
+
+// testSection returns the section with the Type and Value attributes.
+func testSection(typ, value string) *Section {
+	return &Section{Path: "/p", Attrs: []*Attr{
+		{Key: "Persistence", Value: "full"},
+		{Key: "Type", Value: typ},
+		{Key: "Value", Value: value},
+	}}
+}
+
+// sectionErr returns the underlying error of the sectionError.
+func sectionErr(err error) error {
+	if se, ok := err.(*sectionError); ok {
+		return se.err
+	}
+	return err
+}
+
+func TestSection_String(t *testing.T) {
+	tests := []struct {
+		name    string
+		section *Section
+		want    string
+		wantErr error
+	}{
+		{"quoted", testSection(TypeString, `"ENU"`), "ENU", nil},
+		{"escaped", testSection(TypeString, `"say \"hi\" \\o/"`), `say "hi" \\o/`, nil},
+		{"windows path", testSection(TypeString, `"C:\siebel\bin"`), `C:\siebel\bin`, nil},
+		{"trailing backslash", testSection(TypeString, `"C:\siebel\"`), `C:\siebel\`, nil},
+		{"unc path", testSection(TypeString, `"\\srv\fs"`), `\\srv\fs`, nil},
+		{"unquoted", testSection(TypeString, `ENU`), "ENU", nil},
+		{"empty", testSection(TypeString, `""`), "", nil},
+		{"unterminated", testSection(TypeString, `"ENU`), "", errUnbalancedQuotes},
+		{"quote inside", testSection(TypeString, `"a"b"`), "", errUnbalancedQuotes},
+		{"stray quote", testSection(TypeString, `a"b`), "", errUnbalancedQuotes},
+		{"type mismatch", testSection(TypeInteger, `1`), "", errTypeMismatch},
+		{"no type", &Section{Path: "/p"}, "", errNoType},
+		{"no value", &Section{Path: "/p", Attrs: []*Attr{{Key: "Type", Value: TypeString}}}, "", errNoValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.section.String()
+			if sectionErr(err) != tt.wantErr {
+				t.Errorf("Section.String() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Section.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSection_Int(t *testing.T) {
+	tests := []struct {
+		name    string
+		section *Section
+		want    int64
+		wantErr error
+	}{
+		{"ok", testSection(TypeInteger, "100"), 100, nil},
+		{"negative", testSection(TypeInteger, "-1"), -1, nil},
+		{"invalid", testSection(TypeInteger, `"100"`), 0, errInvalidValue},
+		{"type mismatch", testSection(TypeBoolean, "True"), 0, errTypeMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.section.Int()
+			if sectionErr(err) != tt.wantErr {
+				t.Errorf("Section.Int() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Section.Int() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSection_Bool(t *testing.T) {
+	tests := []struct {
+		name    string
+		section *Section
+		want    bool
+		wantErr error
+	}{
+		{"True", testSection(TypeBoolean, "True"), true, nil},
+		{"FALSE", testSection(TypeBoolean, "FALSE"), false, nil},
+		{"Y", testSection(TypeBoolean, "Y"), true, nil},
+		{"n", testSection(TypeBoolean, "n"), false, nil},
+		{"invalid", testSection(TypeBoolean, "maybe"), false, errInvalidValue},
+		{"type mismatch", testSection(TypeString, `"True"`), false, errTypeMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.section.Bool()
+			if sectionErr(err) != tt.wantErr {
+				t.Errorf("Section.Bool() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Section.Bool() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSection_Duration(t *testing.T) {
+	s := testSection(TypeInteger, "30")
+	got, err := s.Duration(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 30*time.Minute {
+		t.Errorf("Section.Duration() = %v, want %v", got, 30*time.Minute)
+	}
+	if err := s.SetDuration(90*time.Second, time.Minute); sectionErr(err) != errInvalidValue {
+		t.Errorf("SetDuration() error = %v, want %v", err, errInvalidValue)
+	}
+	if err := s.SetDuration(2*time.Hour, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := s.Get("Value"); v != "120" {
+		t.Errorf("Value = %q, want %q", v, "120")
+	}
+}
+
+func TestSection_Setters(t *testing.T) {
+	tests := []struct {
+		name      string
+		section   *Section
+		set       func(s *Section) error
+		wantValue string
+		wantErr   error
+	}{
+		{"string", testSection(TypeString, `""`),
+			func(s *Section) error { return s.SetString(`say "hi"`) },
+			`"say \"hi\""`, nil},
+		{"int", testSection(TypeInteger, "1"),
+			func(s *Section) error { return s.SetInt(42) },
+			"42", nil},
+		{"bool", testSection(TypeBoolean, "True"),
+			func(s *Section) error { return s.SetBool(false) },
+			"False", nil},
+		{"adds value",
+			&Section{Path: "/p", Attrs: []*Attr{{Key: "Type", Value: TypeInteger}}},
+			func(s *Section) error { return s.SetInt(7) },
+			"7", nil},
+		{"type mismatch", testSection(TypeString, `"a"`),
+			func(s *Section) error { return s.SetInt(1) },
+			`"a"`, errTypeMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.set(tt.section); sectionErr(err) != tt.wantErr {
+				t.Errorf("setter error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if v, _ := tt.section.Get("Value"); v != tt.wantValue {
+				t.Errorf("Value = %q, want %q", v, tt.wantValue)
+			}
+		})
+	}
+}
+
+func Test_quoteUnquote(t *testing.T) {
+	for _, s := range []string{"", "ENU", `C:\siebel\`, `"quoted"`, `\"`, `\`, `\\srv\fs`, `a\\"b`} {
+		got, err := unquote(quote(s))
+		if err != nil {
+			t.Errorf("unquote(quote(%q)) error = %v", s, err)
+		}
+		if got != s {
+			t.Errorf("unquote(quote(%q)) = %q", s, got)
+		}
+	}
+	// values as they are in the file are written back unchanged.
+	for _, v := range []string{`"ENU"`, `"\\srv\fs"`, `"C:\siebel\"`, `"D:\sba\bin"`, `"say \"hi\""`} {
+		s, err := unquote(v)
+		if err != nil {
+			t.Errorf("unquote(%s) error = %v", v, err)
+		}
+		if got := quote(s); got != v {
+			t.Errorf("quote(unquote(%s)) = %s", v, got)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package siebns
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// checksumWidth is the width of the checksum line without the line ending,
+// the encoded size is padded with spaces up to this width.
+const checksumWidth = 25
+
+// eol returns the line ending used in the file.
+func (hdr *nsHeader) eol() string {
+	if hdr.format.dos {
+		return string(crlf)
+	}
+	return string(crlf[1:])
+}
+
+// WriteTo writes the whole file to w, implementing io.WriterTo.  The
+// encoded size in the header is recomputed, so that it matches the size of
+// the data written.  When nothing was changed, the output is identical to
+// the file that was read.
+func (ns *NSFile) WriteTo(w io.Writer) (int64, error) {
+	data, err := ns.render()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// render returns the contents of the file with the encoded size set.
+func (ns *NSFile) render() ([]byte, error) {
+	if ns.bodyErr != nil {
+		return nil, ns.bodyErr
+	}
+	ns.applyByteOrder()
+	hdr := ns.header
+	if hdr == nil {
+		return nil, errNotInitialised
+	}
+	if hdr.byteOrder == nil {
+		return nil, errByteOrderNil
+	}
+	eol := hdr.eol()
+
+	var buf bytes.Buffer
+	if hdr.format.unicode {
+		buf.Write(bom[:])
+	}
+	buf.WriteString(signature + eol)
+	buf.WriteString(hdr.version.siebel + eol)
+	buf.WriteString(hdr.version.nsfile + eol)
+	cksumOffset := buf.Len()
+	buf.WriteString(strings.Repeat(" ", checksumWidth) + eol)
+
+	ns.body.render(&buf, eol)
+
+	size, err := encodeSize(int64(buf.Len()), hdr.byteOrder)
+	if err != nil {
+		return nil, err
+	}
+	data := buf.Bytes()
+	copy(data[cksumOffset:], size)
+	hdr.offsets.checksum = int64(cksumOffset)
+
+	return data, nil
+}
+
+// render writes the body to buf.  Lines that were read from the file keep
+// their line endings, eol is used for lines that were added.
+func (b *body) render(buf *bytes.Buffer, eol string) {
+	// unterminated is true when the last line written has no line ending,
+	// which may only happen with the last line of the file.
+	var unterminated bool
+	writeLine := func(text string, end string) {
+		if unterminated {
+			buf.WriteString(eol)
+		}
+		buf.WriteString(text)
+		buf.WriteString(end)
+		unterminated = end == ""
+	}
+	writeBlanks := func(blanks []string) {
+		for _, bl := range blanks {
+			trimmed := trimEOL([]byte(bl))
+			writeLine(string(trimmed), bl[len(trimmed):])
+		}
+	}
+
+	writeBlanks(b.preamble)
+	for _, s := range b.sections {
+		writeLine("["+s.Path+"]", lineEOL(s.eol, s.line, eol))
+		writeBlanks(s.blanks)
+		for _, a := range s.Attrs {
+			indent := a.indent
+			if indent == "" {
+				indent = "\t"
+			}
+			writeLine(indent+a.Key+"="+a.Value, lineEOL(a.eol, a.line, eol))
+			writeBlanks(a.blanks)
+		}
+	}
+}
+
+// lineEOL returns the line ending for the line: the original line ending,
+// if the line was read from the file, or the default one otherwise.
+func lineEOL(orig string, line int, eol string) string {
+	if line == 0 {
+		return eol
+	}
+	return orig
+}
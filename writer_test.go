@@ -0,0 +1,121 @@
+package siebns
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// withValidSize returns contents with the encoded size matching the length
+// of contents.
+func withValidSize(contents string) string {
+	data := []byte(contents)
+	hdr, err := readHeader(bytes.NewReader(data))
+	if err != nil {
+		panic(err)
+	}
+	size, err := encodeSize(int64(len(data)), hdr.byteOrder)
+	if err != nil {
+		panic(err)
+	}
+	copy(data[hdr.offsets.checksum:], size)
+	return string(data)
+}
+
+// parseTestNSFile returns the NSFile loaded from the temporary file with
+// contents.
+func parseTestNSFile(t *testing.T, contents string) *NSFile {
+	ns := CreateTestNSFile(contents)
+	if err := ns.load(); err != nil {
+		CloseTestNSFile(ns)
+		t.Fatal(err)
+	}
+	return ns
+}
+
+func TestNSFile_WriteTo(t *testing.T) {
+	dos := strings.Replace(testfileSample, "\n", "\r\n", -1)
+	tests := []struct {
+		name     string
+		contents string
+	}{
+		{"unix", withValidSize(testfileSample)},
+		{"dos", withValidSize(dos)},
+		{"unicode", withValidSize(string(bom[:]) + testfileSample)},
+		{"unicode dos", withValidSize(string(bom[:]) + dos)},
+		{"blank lines and spaces", withValidSize(testfileEmpty + "\n  \n[/enterprises]\n  \tType=empty \n\n")},
+		{"no trailing newline", withValidSize(strings.TrimSuffix(testfileEmpty, "\n"))},
+		{"mixed line endings", withValidSize(testfileEmpty + "[/enterprises]\r\n\tType=empty\r\n")},
+		{"big endian", withValidSize(strings.Replace(testfileSample, "DAMAAAAAAAA=", "AAAAAAAAAww=", 1))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := parseTestNSFile(t, tt.contents)
+			defer CloseTestNSFile(ns)
+
+			var buf bytes.Buffer
+			n, err := ns.WriteTo(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != int64(len(tt.contents)) {
+				t.Errorf("WriteTo() = %d, want %d", n, len(tt.contents))
+			}
+			if diff := cmp.Diff(tt.contents, buf.String()); diff != "" {
+				t.Errorf("WriteTo() mismatch, (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNSFile_WriteToRecomputesSize(t *testing.T) {
+	ns := parseTestNSFile(t, testfileSample)
+	defer CloseTestNSFile(ns)
+
+	// appending the section to the file without the trailing newline
+	s := ns.Section("/enterprises/SBA_84/parameters/MaxTasks")
+	s.Attrs = append(s.Attrs, &Attr{Key: "Description", Value: `"Maximum tasks"`})
+
+	var buf bytes.Buffer
+	if _, err := ns.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := withValidSize(testfileSample + "\tDescription=\"Maximum tasks\"\n")
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("WriteTo() mismatch, (-want,+got):\n%s", diff)
+	}
+
+	size, order, err := decodeSize(buf.Bytes()[ns.header.offsets.checksum:][:checksumSz])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != int64(buf.Len()) || order != binary.LittleEndian {
+		t.Errorf("encoded size = %d, %v, want %d, %v", size, order, buf.Len(), binary.LittleEndian)
+	}
+}
+
+func TestNSFile_WriteToAddsEOL(t *testing.T) {
+	ns := parseTestNSFile(t, strings.TrimSuffix(testfileEmpty, "\n"))
+	defer CloseTestNSFile(ns)
+
+	ns.sections = append(ns.sections, &Section{Path: "/enterprises", Attrs: []*Attr{{Key: "Type", Value: "empty"}}})
+
+	var buf bytes.Buffer
+	if _, err := ns.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := withValidSize(testfileEmpty + "[/enterprises]\n\tType=empty\n")
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("WriteTo() mismatch, (-want,+got):\n%s", diff)
+	}
+}
+
+func TestNSFile_WriteToNotInitialised(t *testing.T) {
+	var ns NSFile
+	if _, err := ns.WriteTo(&bytes.Buffer{}); err != errNotInitialised {
+		t.Errorf("WriteTo() error = %v, want %v", err, errNotInitialised)
+	}
+}